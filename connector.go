@@ -0,0 +1,145 @@
+package hdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+
+	hdbdriver "github.com/SAP/go-hdb/driver"
+)
+
+// openDB opens the connection pool from the configured Connector or DSN. Connections
+// to go-hdb are opened through a driver.Connector so connection attributes like the
+// default schema are applied to every new pooled connection.
+func (dialector Dialector) openDB() (*sql.DB, error) {
+	connector, err := dialector.connector()
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.OpenDB(&sessionConnector{
+		Connector:         connector,
+		statements:        dialector.sessionStatements(),
+		hook:              dialector.ConnectHook,
+		variables:         dialector.SessionVariables,
+		readOnly:          dialector.ReadOnly,
+		retry:             dialector.RetryPolicy,
+		schema:            dialector.NormalizeIdentifier(dialector.DefaultSchema),
+		preserveCase:      dialector.PreserveCase,
+		uuidFormat:        dialector.UUIDFormat,
+		location:          dialector.TimeLocation,
+		checkUintOverflow: dialector.CheckUintOverflow,
+	}), nil
+}
+
+func (dialector Dialector) connector() (driver.Connector, error) {
+	if dialector.Connector != nil {
+		return dialector.Connector, dialector.configureConnector(dialector.Connector)
+	}
+
+	if len(dialector.Hosts) > 0 {
+		return dialector.newFailoverConnector()
+	}
+
+	if !dialector.hasCertificateAuth() && dialector.Token == "" && dialector.RefreshToken == nil {
+		if dialector.DSN == "" {
+			dialector.DSN = dialector.Config.FormatDSN()
+		}
+
+		if dialector.DriverName != "hdb" {
+			return dialector.driverConnector()
+		}
+	}
+
+	connector, err := dialector.newConnector()
+	if err != nil {
+		return nil, err
+	}
+	return connector, dialector.configureConnector(connector)
+}
+
+// driverConnector returns a connector for the DSN of a custom registered DriverName.
+func (dialector Dialector) driverConnector() (driver.Connector, error) {
+	db, err := sql.Open(dialector.DriverName, dialector.DSN)
+	if err != nil {
+		return nil, err
+	}
+	drv := db.Driver()
+	if err = db.Close(); err != nil {
+		return nil, err
+	}
+
+	if driverContext, ok := drv.(driver.DriverContext); ok {
+		return driverContext.OpenConnector(dialector.DSN)
+	}
+	return &dsnConnector{driver: drv, dsn: dialector.DSN}, nil
+}
+
+type dsnConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c *dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// newConnector creates a go-hdb connector for token (JWT), X.509 client certificate or,
+// by default, DSN based basic authentication.
+func (dialector Dialector) newConnector() (*hdbdriver.Connector, error) {
+	switch {
+	case dialector.Token != "" || dialector.RefreshToken != nil:
+		return hdbdriver.NewJWTAuthConnector(dialector.Config.address(), dialector.Token), nil
+	case len(dialector.ClientCert) > 0:
+		return hdbdriver.NewX509AuthConnector(dialector.Config.address(), dialector.ClientCert, dialector.ClientKey), nil
+	case dialector.ClientCertFile != "":
+		return hdbdriver.NewX509AuthConnectorByFiles(dialector.Config.address(), dialector.ClientCertFile, dialector.ClientKeyFile)
+	default:
+		return hdbdriver.NewDSNConnector(dialector.DSN)
+	}
+}
+
+func (dialector Dialector) hasCertificateAuth() bool {
+	return len(dialector.ClientCert) > 0 || dialector.ClientCertFile != ""
+}
+
+func (dialector Dialector) configureConnector(connector driver.Connector) error {
+	c, ok := connector.(*hdbdriver.Connector)
+	if !ok {
+		return nil
+	}
+
+	if dialector.DefaultSchema != "" {
+		c.SetDefaultSchema(dialector.NormalizeIdentifier(dialector.DefaultSchema))
+	}
+	if dialector.Timeout > 0 {
+		c.SetTimeout(dialector.Timeout)
+	}
+	if dialector.PingInterval > 0 {
+		c.SetPingInterval(dialector.PingInterval)
+	}
+	if dialector.TCPKeepAlive != 0 {
+		c.SetTCPKeepAlive(dialector.TCPKeepAlive)
+	}
+
+	if dialector.RefreshPassword != nil {
+		c.SetRefreshPassword(dialector.RefreshPassword)
+	}
+	if dialector.RefreshToken != nil {
+		c.SetRefreshToken(dialector.RefreshToken)
+	}
+	if dialector.RefreshClientCert != nil {
+		c.SetRefreshClientCert(dialector.RefreshClientCert)
+	}
+
+	if dialector.TLSConfig != nil {
+		c.SetTLSConfig(dialector.TLSConfig)
+	} else if dialector.TLSServerName != "" || dialector.TLSInsecureSkipVerify || len(dialector.TLSRootCAFiles) > 0 {
+		return c.SetTLS(dialector.TLSServerName, dialector.TLSInsecureSkipVerify, dialector.TLSRootCAFiles...)
+	}
+	return nil
+}
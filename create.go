@@ -0,0 +1,164 @@
+package hdb
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrNoIdentitySession identity value read on a connection pool without dedicated connections
+var ErrNoIdentitySession = errors.New("cannot read the identity value on the session of the insert")
+
+// Create inserts the statement's values, calling function defaults of blank fields and
+// writing NULL as configured. As HANA supports neither RETURNING nor LastInsertId, blank
+// identity primary keys are filled from CURRENT_IDENTITY_VALUE() read on the connection
+// of the insert.
+func Create(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+
+	if db.Statement.Schema != nil && !db.Statement.Unscoped {
+		for _, c := range db.Statement.Schema.CreateClauses {
+			db.Statement.AddClause(c)
+		}
+	}
+
+	if db.Statement.SQL.Len() == 0 {
+		db.Statement.SQL.Grow(180)
+		db.Statement.AddClauseIfNotExists(clause.Insert{})
+		values := callbacks.ConvertToCreateValues(db.Statement)
+		insertFunctionDefaults(db.Statement, values)
+		insertNulls(db, values)
+		insertFlexibleColumns(db.Statement, &values)
+		db.Statement.AddClause(values)
+		db.Statement.Build(db.Statement.BuildClauses...)
+	}
+
+	if db.DryRun || db.Error != nil {
+		return
+	}
+
+	pkField, rows := identityRows(db)
+
+	connPool := db.Statement.ConnPool
+	if pkField != nil {
+		conn, err := sessionConnPool(db)
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+		if conn != nil {
+			defer conn.Close()
+			connPool = conn
+		}
+	}
+
+	result, err := connPool.ExecContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	db.RowsAffected, _ = result.RowsAffected()
+	if pkField == nil || int64(len(rows)) != db.RowsAffected {
+		return
+	}
+
+	var identity sql.NullInt64
+	if err = connPool.QueryRowContext(db.Statement.Context, "SELECT CURRENT_IDENTITY_VALUE() FROM DUMMY").Scan(&identity); err != nil {
+		db.AddError(err)
+		return
+	}
+	if !identity.Valid {
+		return
+	}
+
+	// the identity value is the key of the last inserted row, earlier rows of a batch
+	// are set backwards by the field's increment. This assumes the rows of a batch got
+	// consecutive values, which inserts of other sessions into the table at the same
+	// time can break; models needing exact keys for batches draw them from a sequence.
+	increment := pkField.AutoIncrementIncrement
+	if increment == 0 {
+		increment = 1
+	}
+	for i, row := range rows {
+		value := identity.Int64 - int64(len(rows)-1-i)*increment
+		switch values := row.Interface().(type) {
+		case map[string]interface{}:
+			values[pkField.DBName] = value
+		default:
+			db.AddError(pkField.Set(db.Statement.Context, row, value))
+		}
+	}
+}
+
+// sessionConnPool returns a connection of the statement's pool to read the identity value
+// on the session of the insert, or nil if the statement already runs on one, like in a
+// transaction or db.Connection.
+func sessionConnPool(db *gorm.DB) (*sql.Conn, error) {
+	switch connPool := db.Statement.ConnPool.(type) {
+	case *sql.Conn, gorm.TxCommitter:
+		return nil, nil
+	case *sql.DB:
+		return connPool.Conn(db.Statement.Context)
+	case gorm.GetDBConnector:
+		sqlDB, err := connPool.GetDBConn()
+		if err != nil {
+			return nil, err
+		}
+		return sqlDB.Conn(db.Statement.Context)
+	}
+	return nil, ErrNoIdentitySession
+}
+
+// identityRows returns the identity primary key of the statement's model with the rows
+// leaving it blank, or nil if there are none, some rows set the key themselves or the
+// server creates no identity columns.
+func identityRows(db *gorm.DB) (*schema.Field, []reflect.Value) {
+	if db.Statement.Schema == nil {
+		return nil, nil
+	}
+	if dialector, ok := db.Dialector.(*Dialector); ok && dialector.DontSupportIdentity {
+		return nil, nil
+	}
+
+	pkField := db.Statement.Schema.PrioritizedPrimaryField
+	if pkField == nil || !pkField.AutoIncrement || sequenceOf(pkField) != "" {
+		return nil, nil
+	}
+
+	isBlank := func(row reflect.Value) bool {
+		if values, ok := row.Interface().(map[string]interface{}); ok {
+			_, ok = values[pkField.DBName]
+			return !ok
+		}
+		_, isZero := pkField.ValueOf(db.Statement.Context, row)
+		return isZero
+	}
+
+	var rows []reflect.Value
+	rv := reflect.Indirect(db.Statement.ReflectValue)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if row := reflect.Indirect(rv.Index(i)); isBlank(row) {
+				rows = append(rows, row)
+			}
+		}
+	case reflect.Struct, reflect.Map:
+		if isBlank(rv) {
+			rows = append(rows, rv)
+		}
+	}
+
+	if len(rows) == 0 || (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && len(rows) != rv.Len() {
+		return nil, nil
+	}
+	return pkField, rows
+}
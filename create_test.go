@@ -0,0 +1,42 @@
+package hdb
+
+import (
+	"reflect"
+	"testing"
+)
+
+type Counted struct {
+	ID   uint64 `gorm:"primaryKey;autoIncrement"`
+	Name string
+}
+
+func TestIdentityRows(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		rows   []Counted
+		blank  int
+	}{
+		{name: "blank keys", rows: []Counted{{Name: "a"}, {Name: "b"}}, blank: 2},
+		{name: "some keys set", rows: []Counted{{ID: 1, Name: "a"}, {Name: "b"}}},
+		{name: "no identity", config: Config{DontSupportIdentity: true}, rows: []Counted{{Name: "a"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := dryRunDB(t, tt.config)
+			if err := db.Statement.Parse(&tt.rows); err != nil {
+				t.Fatalf("failed to parse Counted: %v", err)
+			}
+			db.Statement.ReflectValue = reflect.ValueOf(tt.rows)
+
+			field, rows := identityRows(db)
+			if len(rows) != tt.blank {
+				t.Errorf("got %d rows, want %d", len(rows), tt.blank)
+			}
+			if (field != nil) != (tt.blank > 0) {
+				t.Errorf("got field %v, want one only for blank keys", field)
+			}
+		})
+	}
+}
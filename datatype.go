@@ -0,0 +1,107 @@
+package hdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("datatype", DataTypeSerializer{})
+}
+
+// Binder converts a value of a registered Go type to a value go-hdb binds.
+type Binder func(value interface{}) (driver.Value, error)
+
+// Scanner sets dest, a pointer to a registered Go type, from a value read from its column.
+type Scanner func(dest, src interface{}) error
+
+type dataType struct {
+	name    string
+	binder  Binder
+	scanner Scanner
+}
+
+var dataTypes sync.Map
+
+// RegisterDataType creates fields of the type of goType, e.g. LegacyDate{}, as hanaType
+// columns, like DAYDATE or ST_POINT(4326). Values of the type are passed through binder
+// and read through scanner, both optional. Fields of types that don't implement
+// sql.Scanner read through scanner with the datatype serializer:
+//
+//	hdb.RegisterDataType(LegacyDate{}, "DAYDATE", bindLegacyDate, scanLegacyDate)
+//
+//	Day LegacyDate `gorm:"serializer:datatype"`
+func RegisterDataType(goType interface{}, hanaType string, binder Binder, scanner Scanner) {
+	dataTypes.Store(indirectType(reflect.TypeOf(goType)), dataType{name: hanaType, binder: binder, scanner: scanner})
+}
+
+func lookupDataType(t reflect.Type) (dataType, bool) {
+	if t == nil {
+		return dataType{}, false
+	}
+	value, ok := dataTypes.Load(indirectType(t))
+	if !ok {
+		return dataType{}, false
+	}
+	return value.(dataType), true
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// customValue binds values of registered types with their binder.
+func customValue(value interface{}) (driver.Value, bool, error) {
+	rv := reflect.ValueOf(value)
+	dataType, ok := lookupDataType(reflect.TypeOf(value))
+	if !ok || dataType.binder == nil {
+		return nil, false, nil
+	}
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, true, nil
+	}
+
+	bound, err := dataType.binder(reflect.Indirect(rv).Interface())
+	return bound, true, err
+}
+
+// DataTypeSerializer reads and writes fields of types registered with RegisterDataType
+// through their scanner and binder. It is registered as datatype.
+type DataTypeSerializer struct{}
+
+func (DataTypeSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType)
+	if dbValue != nil {
+		dataType, ok := lookupDataType(field.FieldType)
+		if !ok || dataType.scanner == nil {
+			return fmt.Errorf("no scanner registered for field type %s", field.FieldType)
+		}
+
+		dest := fieldValue.Interface()
+		if field.FieldType.Kind() == reflect.Ptr {
+			fieldValue.Elem().Set(reflect.New(field.FieldType.Elem()))
+			dest = fieldValue.Elem().Interface()
+		}
+		if err := dataType.scanner(dest, dbValue); err != nil {
+			return err
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+func (DataTypeSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	if value, ok, err := customValue(fieldValue); ok {
+		return value, err
+	}
+	return fieldValue, nil
+}
@@ -0,0 +1,223 @@
+package hdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("decimal", DecimalSerializer{})
+}
+
+var (
+	decimalType     = reflect.TypeOf(decimal.Decimal{})
+	nullDecimalType = reflect.TypeOf(decimal.NullDecimal{})
+	ratType         = reflect.TypeOf(big.Rat{})
+)
+
+// isDecimalType reports whether t is decimal.Decimal, decimal.NullDecimal or big.Rat, or a
+// pointer to one of them, which are created as DECIMAL columns.
+func isDecimalType(t reflect.Type) bool {
+	switch indirectType(t) {
+	case decimalType, nullDecimalType, ratType:
+		return true
+	}
+	return false
+}
+
+// DecimalSerializer stores decimal.Decimal, decimal.NullDecimal and big.Rat fields as DECIMAL
+// columns, passing values as the *big.Rat go-hdb uses for decimals. It is registered as
+// decimal, fields created from its default type take their precision and scale from tags.
+// Fields of these types are read and written as decimals without it as well, it is needed
+// for queries gorm doesn't run through Find, like Scan and Pluck, only.
+//
+//	Amount decimal.Decimal `gorm:"serializer:decimal;precision:15;scale:2"`
+type DecimalSerializer struct{}
+
+func (DecimalSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	rat, err := ratOf(dbValue)
+	if err != nil {
+		return err
+	}
+
+	fieldValue := reflect.New(field.FieldType)
+	if rat != nil {
+		switch value := fieldValue.Interface().(type) {
+		case *decimal.Decimal:
+			*value = decimalOf(rat)
+		case **decimal.Decimal:
+			d := decimalOf(rat)
+			*value = &d
+		case *decimal.NullDecimal:
+			*value = decimal.NullDecimal{Decimal: decimalOf(rat), Valid: true}
+		case *big.Rat:
+			value.Set(rat)
+		case **big.Rat:
+			*value = rat
+		default:
+			return fmt.Errorf("invalid field type %s for DecimalSerializer", field.FieldType)
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+func (DecimalSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value, ok := decimalValue(fieldValue)
+	if !ok {
+		return nil, fmt.Errorf("invalid field type %T for DecimalSerializer", fieldValue)
+	}
+	return value, nil
+}
+
+// decimalValue converts decimal values to the *big.Rat go-hdb binds to DECIMAL parameters.
+func decimalValue(value interface{}) (driver.Value, bool) {
+	switch v := value.(type) {
+	case decimal.Decimal:
+		return v.Rat(), true
+	case *decimal.Decimal:
+		if v == nil {
+			return nil, true
+		}
+		return v.Rat(), true
+	case decimal.NullDecimal:
+		if !v.Valid {
+			return nil, true
+		}
+		return v.Decimal.Rat(), true
+	case *decimal.NullDecimal:
+		if v == nil || !v.Valid {
+			return nil, true
+		}
+		return v.Decimal.Rat(), true
+	case big.Rat:
+		return &v, true
+	case *big.Rat:
+		if v == nil {
+			return nil, true
+		}
+		return v, true
+	}
+	return nil, false
+}
+
+func ratOf(dbValue interface{}) (*big.Rat, error) {
+	switch v := dbValue.(type) {
+	case nil:
+		return nil, nil
+	case *big.Rat:
+		return v, nil
+	case int64:
+		return new(big.Rat).SetInt64(v), nil
+	case float64:
+		return new(big.Rat).SetFloat64(v), nil
+	case []byte:
+		return ratOf(string(v))
+	case string:
+		if rat, ok := new(big.Rat).SetString(v); ok {
+			return rat, nil
+		}
+	}
+	return nil, fmt.Errorf("decimal: invalid data type %T", dbValue)
+}
+
+// decimalOf converts rat to a decimal.Decimal, exactly for the decimal fractions read
+// from DECIMAL columns.
+func decimalOf(rat *big.Rat) decimal.Decimal {
+	var (
+		denom     = new(big.Int).Set(rat.Denom())
+		remainder = new(big.Int)
+		scale     int32
+	)
+
+	for denom.Cmp(big.NewInt(1)) != 0 {
+		divided := false
+		for _, divisor := range []int64{10, 2, 5} {
+			quotient, _ := new(big.Int).QuoRem(denom, big.NewInt(divisor), remainder)
+			if remainder.Sign() == 0 {
+				denom, divided = quotient, true
+				break
+			}
+		}
+
+		if !divided {
+			return decimal.NewFromBigRat(rat, maxDecimalPrecision)
+		}
+		scale++
+	}
+	return decimal.NewFromBigRat(rat, scale)
+}
+
+// hasDecimalField reports whether s has decimal fields without serializer, which Query
+// scans through decimalRows.
+func hasDecimalField(s *schema.Schema) bool {
+	if s == nil {
+		return false
+	}
+	for _, field := range s.Fields {
+		if field.Serializer == nil && isDecimalType(field.FieldType) {
+			return true
+		}
+	}
+	return false
+}
+
+// decimalRows scans the *big.Rat go-hdb reads from DECIMAL columns into the decimal fields
+// gorm scans, which decimal.Decimal and big.Rat can't read themselves.
+type decimalRows struct {
+	gorm.Rows
+}
+
+func (r decimalRows) Scan(dest ...interface{}) error {
+	// gorm sets its fields from the values of dest after the scan
+	scanned := make([]interface{}, len(dest))
+	for i, d := range dest {
+		switch d.(type) {
+		case *decimal.Decimal, *decimal.NullDecimal, *big.Rat:
+			scanned[i] = decimalDest{dest: d}
+		default:
+			scanned[i] = d
+		}
+	}
+	return r.Rows.Scan(scanned...)
+}
+
+// decimalDest sets a decimal.Decimal, decimal.NullDecimal or big.Rat from a decimal. NULL
+// sets decimal.Decimal and big.Rat to zero and decimal.NullDecimal to invalid.
+type decimalDest struct {
+	dest interface{}
+}
+
+func (d decimalDest) Scan(src interface{}) error {
+	rat, err := ratOf(src)
+	if err != nil {
+		return err
+	}
+
+	switch dest := d.dest.(type) {
+	case *decimal.Decimal:
+		*dest = decimal.Decimal{}
+		if rat != nil {
+			*dest = decimalOf(rat)
+		}
+	case *decimal.NullDecimal:
+		*dest = decimal.NullDecimal{}
+		if rat != nil {
+			*dest = decimal.NullDecimal{Decimal: decimalOf(rat), Valid: true}
+		}
+	case *big.Rat:
+		dest.SetInt64(0)
+		if rat != nil {
+			dest.Set(rat)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package hdb
+
+import (
+	"database/sql"
+	"math/big"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ratRows scans a row of go-hdb decimals the way database/sql does into scanners.
+type ratRows struct {
+	gorm.Rows
+	values []interface{}
+}
+
+func (r ratRows) Scan(dest ...interface{}) error {
+	for i, d := range dest {
+		if err := d.(sql.Scanner).Scan(r.values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestDecimalRows(t *testing.T) {
+	var (
+		amount   decimal.Decimal
+		discount = decimal.NullDecimal{Decimal: decimal.NewFromInt(1), Valid: true}
+		ratio    big.Rat
+	)
+
+	rows := decimalRows{Rows: ratRows{values: []interface{}{big.NewRat(1234, 100), nil, big.NewRat(1, 8)}}}
+	dest := []interface{}{&amount, &discount, &ratio}
+	if err := rows.Scan(dest...); err != nil {
+		t.Fatalf("failed to scan decimals: %v", err)
+	}
+
+	if amount.String() != "12.34" {
+		t.Errorf("got %s, want 12.34", amount)
+	}
+	if discount.Valid {
+		t.Errorf("got %v from NULL, want an invalid decimal", discount)
+	}
+	if ratio.Cmp(big.NewRat(1, 8)) != 0 {
+		t.Errorf("got %s, want 1/8", ratio.String())
+	}
+	if _, ok := dest[0].(*decimal.Decimal); !ok {
+		t.Errorf("got %T in dest, want the destinations of gorm kept", dest[0])
+	}
+}
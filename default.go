@@ -0,0 +1,74 @@
+package hdb
+
+import (
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// defaultFunctions are the functions usable as column defaults.
+var defaultFunctions = map[string]bool{
+	"CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true,
+	"CURRENT_UTCDATE": true, "CURRENT_UTCTIME": true, "CURRENT_UTCTIMESTAMP": true,
+	"CURRENT_USER": true, "CURRENT_SCHEMA": true, "SESSION_USER": true, "SYSUUID": true,
+}
+
+// functionDefault returns the default of a field calling a function or drawing a sequence's
+// NEXTVAL, which goes into DDL and inserts as is rather than as a string.
+func functionDefault(field *schema.Field) (string, bool) {
+	if !field.HasDefaultValue {
+		return "", false
+	}
+
+	value := strings.TrimSpace(field.DefaultValue)
+	name := strings.TrimSuffix(strings.ToUpper(value), "()")
+	if defaultFunctions[name] || strings.HasSuffix(name, ".NEXTVAL") {
+		return value, true
+	}
+	return "", false
+}
+
+// sameDefault reports whether a column's default is the function default value, with or
+// without parentheses.
+func sameDefault(columnDefault, value string) bool {
+	return strings.EqualFold(strings.TrimSuffix(columnDefault, "()"), strings.TrimSuffix(value, "()"))
+}
+
+// insertFunctionDefaults replaces the function defaults gorm inserts as strings for blank
+// fields with calls of the functions and clears the fields again.
+func insertFunctionDefaults(stmt *gorm.Statement, values clause.Values) {
+	if stmt.Schema == nil {
+		return
+	}
+
+	rv := reflect.Indirect(stmt.ReflectValue)
+	for idx, column := range values.Columns {
+		field := stmt.Schema.LookUpField(column.Name)
+		if field == nil || field.DefaultValueInterface == nil {
+			continue
+		}
+		value, ok := functionDefault(field)
+		if !ok {
+			continue
+		}
+
+		for i, row := range values.Values {
+			if row[idx] != field.DefaultValueInterface {
+				continue
+			}
+			row[idx] = clause.Expr{SQL: value}
+
+			switch rv.Kind() {
+			case reflect.Slice, reflect.Array:
+				if i < rv.Len() {
+					stmt.AddError(field.Set(stmt.Context, reflect.Indirect(rv.Index(i)), reflect.Zero(field.FieldType).Interface()))
+				}
+			case reflect.Struct:
+				stmt.AddError(field.Set(stmt.Context, rv, reflect.Zero(field.FieldType).Interface()))
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package hdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	hdbdriver "github.com/SAP/go-hdb/driver"
+	"gorm.io/gorm"
+)
+
+// FormatDSN assembles a go-hdb data source name from the structured connection fields,
+// escaping user name and password as needed.
+func (c Config) FormatDSN() string {
+	values := url.Values{}
+	if c.DefaultSchema != "" {
+		values.Set(hdbdriver.DSNDefaultSchema, c.DefaultSchema)
+	}
+	if c.Timeout > 0 {
+		values.Set(hdbdriver.DSNTimeout, strconv.Itoa(int(c.Timeout/time.Second)))
+	}
+	if c.PingInterval > 0 {
+		values.Set(hdbdriver.DSNPingInterval, strconv.Itoa(int(c.PingInterval/time.Second)))
+	}
+	if c.TLSServerName != "" {
+		values.Set(hdbdriver.DSNTLSServerName, c.TLSServerName)
+	}
+	if c.TLSInsecureSkipVerify {
+		values.Set(hdbdriver.DSNTLSInsecureSkipVerify, "true")
+	}
+	for _, file := range c.TLSRootCAFiles {
+		values.Add(hdbdriver.DSNTLSRootCAFile, file)
+	}
+
+	u := url.URL{Scheme: "hdb", Host: c.address(), RawQuery: values.Encode()}
+	if c.User != "" {
+		if c.Password != "" {
+			u.User = url.UserPassword(c.User, c.Password)
+		} else {
+			u.User = url.User(c.User)
+		}
+	}
+	return u.String()
+}
+
+// ParseDSN parses a go-hdb data source name into a Config.
+func ParseDSN(dsn string) (*Config, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "hdb" {
+		return nil, fmt.Errorf("invalid hdb dsn scheme: %q", u.Scheme)
+	}
+
+	config := &Config{DSN: dsn, Host: u.Host}
+	if host, port, err := net.SplitHostPort(u.Host); err == nil {
+		config.Host = host
+		if config.Port, err = strconv.Atoi(port); err != nil {
+			return nil, fmt.Errorf("invalid hdb dsn port: %q", port)
+		}
+	}
+
+	if u.User != nil {
+		config.User = u.User.Username()
+		config.Password, _ = u.User.Password()
+	}
+
+	query := u.Query()
+	config.DefaultSchema = query.Get(hdbdriver.DSNDefaultSchema)
+	config.TLSServerName = query.Get(hdbdriver.DSNTLSServerName)
+	config.TLSRootCAFiles = query[hdbdriver.DSNTLSRootCAFile]
+	if values, ok := query[hdbdriver.DSNTLSInsecureSkipVerify]; ok {
+		config.TLSInsecureSkipVerify = len(values) == 0 || values[0] == "" || values[0] == "true"
+	}
+	if value := query.Get(hdbdriver.DSNTimeout); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hdb dsn timeout: %q", value)
+		}
+		config.Timeout = time.Duration(seconds) * time.Second
+	}
+	if value := query.Get(hdbdriver.DSNPingInterval); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hdb dsn ping interval: %q", value)
+		}
+		config.PingInterval = time.Duration(seconds) * time.Second
+	}
+
+	return config, nil
+}
+
+func (c Config) address() string {
+	if c.Port > 0 {
+		return net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+	}
+	return c.Host
+}
+
+// resolveDatabase looks up the SQL port of the tenant database named by DatabaseName
+// via the system database and reconnects to it when the current connection points elsewhere.
+func (dialector Dialector) resolveDatabase(db *gorm.DB) error {
+	sqlDB, ok := db.ConnPool.(*sql.DB)
+	if !ok {
+		return nil
+	}
+
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var info *hdbdriver.DBConnectInfo
+	if err = conn.Raw(func(driverConn interface{}) (err error) {
+		if hdbConn, ok := driverConn.(hdbdriver.Conn); ok {
+			info, err = hdbConn.DBConnectInfo(ctx, dialector.DatabaseName)
+		}
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if info == nil || info.IsConnected {
+		return nil
+	}
+
+	dialector.Config.Host, dialector.Config.Port = info.Host, info.Port
+	dialector.Config.DSN = dialector.Config.FormatDSN()
+	if err = sqlDB.Close(); err != nil {
+		return err
+	}
+	db.ConnPool, err = dialector.openDB()
+	return err
+}
@@ -0,0 +1,120 @@
+package hdb
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// enumValues returns the values allowed by a field's enum tag, a comma separated list of
+// optionally single quoted values like enum:'A','B','C'.
+func enumValues(field *schema.Field) []string {
+	tag := field.TagSettings["ENUM"]
+	if tag == "" {
+		return nil
+	}
+
+	var (
+		values []string
+		value  strings.Builder
+		quoted bool
+	)
+	for i := 0; i < len(tag); i++ {
+		switch c := tag[i]; {
+		case c == '\'' && quoted && i+1 < len(tag) && tag[i+1] == '\'':
+			value.WriteByte(c)
+			i++
+		case c == '\'':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			values = append(values, strings.TrimSpace(value.String()))
+			value.Reset()
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return append(values, strings.TrimSpace(value.String()))
+}
+
+// enumSize returns the length of the longest enum value.
+func enumSize(values []string) (size int) {
+	for _, value := range values {
+		if n := len([]rune(value)); n > size {
+			size = n
+		}
+	}
+	return size
+}
+
+// enumCondition returns the check condition restricting the column to the values. The
+// values are literals, as DDL takes no parameters.
+func enumCondition(stmt *gorm.Statement, column string, values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quoteString(value)
+	}
+	return stmt.Quote(clause.Column{Name: column}) + " IN (" + strings.Join(quoted, ", ") + ")"
+}
+
+// createEnumChecks adds the check constraints restricting the enum fields of the
+// statement's model to their values, or of a single field when names are given.
+func (m Migrator) createEnumChecks(stmt *gorm.Statement, names ...string) error {
+	if stmt.Schema == nil {
+		return nil
+	}
+
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" || (len(names) > 0 && field.DBName != names[0]) {
+			continue
+		}
+		if values := enumValues(field); len(values) > 0 {
+			if err := m.addEnumCheck(stmt, field, values); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// enumCheckName returns the name of an enum field's check constraint, suffixed to keep it
+// apart from the constraints of check tags and IS JSON checks on the same column.
+func (m Migrator) enumCheckName(stmt *gorm.Statement, field *schema.Field) string {
+	return m.DB.NamingStrategy.CheckerName(stmt.Table, field.DBName+"_ENUM")
+}
+
+func (m Migrator) addEnumCheck(stmt *gorm.Statement, field *schema.Field, values []string) error {
+	return m.createCheck(stmt, m.enumCheckName(stmt, field), enumCondition(stmt, field.DBName, values))
+}
+
+// migrateEnumCheck replaces the check constraint of an enum field whose allowed values
+// changed.
+func (m Migrator) migrateEnumCheck(value interface{}, field *schema.Field) error {
+	values := enumValues(field)
+	if len(values) == 0 {
+		return nil
+	}
+
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		conditions, err := m.checkConditions(stmt)
+		if err != nil {
+			return err
+		}
+
+		name := m.enumCheckName(stmt, field)
+		condition := enumCondition(stmt, field.DBName, values)
+		if current, ok := conditions[m.NormalizeIdentifier(name)]; ok {
+			if normalizeCondition(current) == normalizeCondition(condition) {
+				return nil
+			}
+
+			if err := m.DB.Exec(
+				"ALTER TABLE ? DROP CONSTRAINT ?", m.CurrentTable(stmt), clause.Column{Name: name},
+			).Error; err != nil {
+				return err
+			}
+		}
+		return m.createCheck(stmt, name, condition)
+	})
+}
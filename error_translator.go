@@ -0,0 +1,39 @@
+package hdb
+
+import (
+	"errors"
+
+	hdbdriver "github.com/SAP/go-hdb/driver"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNullValueNotAllowed null value inserted into a NOT NULL column
+	ErrNullValueNotAllowed = errors.New("cannot insert NULL or update to NULL")
+	// ErrTableExists table already exists
+	ErrTableExists = errors.New("cannot use duplicate table name")
+	// ErrTableNotFound table does not exist
+	ErrTableNotFound = errors.New("invalid table name")
+	// ErrUintOverflow unsigned value exceeds BIGINT
+	ErrUintOverflow = errors.New("unsigned integer overflows BIGINT")
+)
+
+// The error codes to map hdb errors to gorm errors, see the SQL error codes section of the SAP HANA SQL reference.
+var errCodes = map[int]error{
+	259: ErrTableNotFound,
+	287: ErrNullValueNotAllowed,
+	288: ErrTableExists,
+	301: gorm.ErrDuplicatedKey,
+	461: gorm.ErrForeignKeyViolated,
+	462: gorm.ErrForeignKeyViolated,
+}
+
+func (dialector Dialector) Translate(err error) error {
+	var hdbErr hdbdriver.Error
+	if errors.As(err, &hdbErr) {
+		if translatedErr, found := errCodes[hdbErr.Code()]; found {
+			return translatedErr
+		}
+	}
+	return err
+}
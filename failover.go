@@ -0,0 +1,65 @@
+package hdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"net"
+	"strconv"
+)
+
+// failoverConnector connects to the first reachable of several HANA endpoints, e.g. the
+// primary and secondary sites of a system replication setup. It moves on to the next
+// endpoint only on errors of the RetryPolicy's kind, by default network errors like the
+// refused connections of standby hosts and lost connections; others, like a wrong
+// password, are returned right away.
+type failoverConnector struct {
+	connectors []driver.Connector
+	policy     *RetryPolicy
+}
+
+func (c *failoverConnector) Connect(ctx context.Context) (conn driver.Conn, err error) {
+	for _, connector := range c.connectors {
+		if conn, err = connector.Connect(ctx); err == nil {
+			return conn, nil
+		}
+
+		if ctx.Err() != nil || !c.policy.retryable(err) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+func (c *failoverConnector) Driver() driver.Driver {
+	return c.connectors[0].Driver()
+}
+
+func (dialector Dialector) newFailoverConnector() (*failoverConnector, error) {
+	addresses := dialector.Hosts
+	if dialector.Host != "" {
+		addresses = append([]string{dialector.Config.address()}, addresses...)
+	}
+
+	failover := &failoverConnector{policy: dialector.RetryPolicy}
+	for _, address := range addresses {
+		config := *dialector.Config
+		config.DSN, config.Hosts = "", nil
+		config.Host, config.Port = address, 0
+		if host, port, err := net.SplitHostPort(address); err == nil {
+			config.Host = host
+			config.Port, _ = strconv.Atoi(port)
+		}
+		config.DSN = config.FormatDSN()
+
+		hostDialector := Dialector{Config: &config}
+		connector, err := hostDialector.newConnector()
+		if err != nil {
+			return nil, err
+		}
+		if err = hostDialector.configureConnector(connector); err != nil {
+			return nil, err
+		}
+		failover.connectors = append(failover.connectors, connector)
+	}
+	return failover, nil
+}
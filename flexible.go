@@ -0,0 +1,248 @@
+package hdb
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
+)
+
+// FlexibleColumns holds the dynamic columns of a row of a schema-flexible table by name. A
+// model with a FlexibleColumns field is created WITH SCHEMA FLEXIBILITY: inserts and updates
+// write its entries as columns, which HANA adds to the table as needed, and queries read the
+// columns missing from the model into it. HANA stores the names of dynamic columns in upper
+// case unless PreserveCase is set:
+//
+//	type Reading struct {
+//		ID         uint
+//		Device     string
+//		Attributes hdb.FlexibleColumns
+//	}
+//
+//	db.Create(&Reading{Device: "d1", Attributes: hdb.FlexibleColumns{"TEMPERATURE": 21.5}})
+//	// INSERT INTO "READINGS" ("DEVICE","TEMPERATURE") VALUES (?,?)
+type FlexibleColumns map[string]interface{}
+
+// GormDataType keeps gorm from taking FlexibleColumns fields for relations.
+func (FlexibleColumns) GormDataType() string {
+	return "flexible"
+}
+
+var flexibleColumnsType = reflect.TypeOf(FlexibleColumns(nil))
+
+// isFlexibleField reports whether field holds the dynamic columns of its row.
+func isFlexibleField(field *schema.Field) bool {
+	return field.IndirectFieldType == flexibleColumnsType
+}
+
+// flexibleFieldOf returns the FlexibleColumns field of a model, or nil if it has none.
+func flexibleFieldOf(s *schema.Schema) *schema.Field {
+	if s == nil {
+		return nil
+	}
+	for _, field := range s.Fields {
+		if isFlexibleField(field) {
+			return field
+		}
+	}
+	return nil
+}
+
+// flexibleValue returns the dynamic columns of a field value.
+func flexibleValue(value interface{}) FlexibleColumns {
+	switch columns := value.(type) {
+	case FlexibleColumns:
+		return columns
+	case *FlexibleColumns:
+		if columns != nil {
+			return *columns
+		}
+	}
+	return nil
+}
+
+// insertFlexibleColumns replaces the FlexibleColumns column of inserted rows by the dynamic
+// columns of any row, binding NULL for rows without them.
+func insertFlexibleColumns(stmt *gorm.Statement, values *clause.Values) {
+	field := flexibleFieldOf(stmt.Schema)
+	if field == nil {
+		return
+	}
+
+	idx := -1
+	for i, column := range values.Columns {
+		if column.Name == field.DBName {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return
+	}
+
+	var (
+		rows  = make([]FlexibleColumns, len(values.Values))
+		names []string
+		seen  = map[string]bool{}
+	)
+	for i, row := range values.Values {
+		rows[i] = flexibleValue(row[idx])
+		for name := range rows[i] {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		values.Values[i] = append(row[:idx:idx], row[idx+1:]...)
+	}
+	values.Columns = append(values.Columns[:idx:idx], values.Columns[idx+1:]...)
+
+	sort.Strings(names)
+	for _, name := range names {
+		values.Columns = append(values.Columns, clause.Column{Name: name})
+	}
+	for i := range values.Values {
+		for _, name := range names {
+			values.Values[i] = append(values.Values[i], rows[i][name])
+		}
+	}
+}
+
+// updateFlexibleColumns replaces the assignment of the FlexibleColumns column by the
+// assignments of its dynamic columns, setting those with nil values to NULL.
+func updateFlexibleColumns(stmt *gorm.Statement, set clause.Set) clause.Set {
+	field := flexibleFieldOf(stmt.Schema)
+	if field == nil {
+		return set
+	}
+
+	for i, assignment := range set {
+		if assignment.Column.Name != field.DBName {
+			continue
+		}
+
+		columns := flexibleValue(assignment.Value)
+		names := make([]string, 0, len(columns))
+		for name := range columns {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		updated := append(clause.Set{}, set[:i]...)
+		for _, name := range names {
+			updated = append(updated, clause.Assignment{Column: clause.Column{Name: name}, Value: columns[name]})
+		}
+		return append(updated, set[i+1:]...)
+	}
+	return set
+}
+
+// Query runs queries like gorm does, reading the columns of schema-flexible tables missing
+// from their model into its FlexibleColumns field and decimals into the decimal fields of
+// the model.
+func Query(db *gorm.DB) {
+	field := flexibleFieldOf(db.Statement.Schema)
+	if field == nil && !hasDecimalField(db.Statement.Schema) {
+		callbacks.Query(db)
+		return
+	}
+
+	if db.Error == nil {
+		callbacks.BuildQuerySQL(db)
+
+		if !db.DryRun && db.Error == nil {
+			rows, err := db.Statement.ConnPool.QueryContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
+			if err != nil {
+				db.AddError(err)
+				return
+			}
+			defer func() {
+				db.AddError(rows.Close())
+			}()
+
+			if field == nil {
+				gorm.Scan(decimalRows{Rows: rows}, db, 0)
+				return
+			}
+
+			flexibleRows := &flexibleRows{Rows: decimalRows{Rows: rows}, schema: db.Statement.Schema}
+			gorm.Scan(flexibleRows, db, 0)
+			flexibleRows.assign(db, field)
+		}
+	}
+}
+
+// flexibleRows collects the columns of the rows scanned by gorm that are missing from the
+// model, which gorm scans into placeholders.
+type flexibleRows struct {
+	gorm.Rows
+	schema  *schema.Schema
+	dynamic map[int]string
+	rows    []FlexibleColumns
+}
+
+func (r *flexibleRows) Scan(dest ...interface{}) error {
+	if err := r.Rows.Scan(dest...); err != nil {
+		return err
+	}
+
+	if r.dynamic == nil {
+		r.dynamic = map[int]string{}
+		columns, err := r.Columns()
+		if err != nil {
+			return err
+		}
+		for idx, column := range columns {
+			if !r.isModelColumn(column) && len(utils.SplitNestedRelationName(column)) == 1 {
+				r.dynamic[idx] = column
+			}
+		}
+	}
+
+	row := FlexibleColumns{}
+	for idx, column := range r.dynamic {
+		if idx >= len(dest) {
+			continue
+		}
+		if value, ok := dest[idx].(*interface{}); ok && *value != nil {
+			row[column] = *value
+		}
+	}
+	r.rows = append(r.rows, row)
+	return nil
+}
+
+// isModelColumn reports whether column is of a field of the model, which HANA returns in
+// upper case unless the model's names preserve case.
+func (r *flexibleRows) isModelColumn(column string) bool {
+	if r.schema.LookUpField(column) != nil {
+		return true
+	}
+	for _, dbName := range r.schema.DBNames {
+		if strings.EqualFold(dbName, column) {
+			return true
+		}
+	}
+	return false
+}
+
+// assign sets the FlexibleColumns field of the scanned models, in the order of their rows.
+func (r *flexibleRows) assign(db *gorm.DB, field *schema.Field) {
+	rv := reflect.Indirect(db.Statement.ReflectValue)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len() && i < len(r.rows); i++ {
+			if elem := reflect.Indirect(rv.Index(i)); elem.Type() == r.schema.ModelType {
+				db.AddError(field.Set(db.Statement.Context, elem, r.rows[i]))
+			}
+		}
+	case reflect.Struct:
+		if rv.Type() == r.schema.ModelType && len(r.rows) > 0 {
+			db.AddError(field.Set(db.Statement.Context, rv, r.rows[0]))
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package hdb
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+// generatedOf returns the expression of a field tagged with generated:EXPR, whose column
+// HANA computes as GENERATED ALWAYS AS (EXPR).
+func generatedOf(field *schema.Field) string {
+	return field.TagSettings["GENERATED"]
+}
+
+// isGeneratedField reports whether HANA computes the column of a field, as generated
+// column or as start or end of the validity of a row version.
+func isGeneratedField(field *schema.Field) bool {
+	return generatedOf(field) != "" || systemTimeOf(field) != ""
+}
+
+// omitGenerated omits generated columns from inserts and updates, as HANA rejects
+// writing them.
+func omitGenerated(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	for _, field := range db.Statement.Schema.Fields {
+		if field.DBName != "" && isGeneratedField(field) {
+			db.Statement.Omits = append(db.Statement.Omits, field.DBName)
+		}
+	}
+}
+
+// catalogColumnType names the embedded migrator.ColumnType apart from its ColumnType method.
+type catalogColumnType = migrator.ColumnType
+
+// generatedColumnType is a column whose GENERATION_TYPE is ALWAYS AS.
+type generatedColumnType struct {
+	tableColumnType
+}
+
+func isGenerated(columnType interface{}) bool {
+	_, ok := columnType.(generatedColumnType)
+	return ok
+}
@@ -1,9 +1,17 @@
 module github.com/revolveyao/hdb
 
-go 1.16
+go 1.18
 
 require (
 	github.com/SAP/go-hdb v0.108.0
+	github.com/shopspring/decimal v1.4.0
+	gorm.io/gorm v1.25.12
+)
+
+require (
+	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/jinzhu/now v1.1.5 // indirect
-	gorm.io/gorm v1.23.8
+	golang.org/x/crypto v0.0.0-20220622213112-05595931fe9d // indirect
+	golang.org/x/exp v0.0.0-20220713135740-79cabaa25d75 // indirect
+	golang.org/x/text v0.14.0 // indirect
 )
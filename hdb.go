@@ -1,311 +1,600 @@
-package hdb
-
-import (
-	"database/sql"
-	"fmt"
-	"math"
-	"strings"
-	"time"
-
-	_ "github.com/SAP/go-hdb/driver"
-	"gorm.io/gorm"
-	"gorm.io/gorm/callbacks"
-	"gorm.io/gorm/clause"
-	"gorm.io/gorm/logger"
-	"gorm.io/gorm/migrator"
-	"gorm.io/gorm/schema"
-)
-
-type Config struct {
-	DriverName                string
-	DSN                       string
-	Conn                      gorm.ConnPool
-	SkipInitializeWithVersion bool
-	DefaultStringSize         uint
-	DefaultDatetimePrecision  *int
-	DisableDatetimePrecision  bool
-	DontSupportRenameIndex    bool
-	DontSupportRenameColumn   bool
-	DontSupportForShareClause bool
-}
-
-type Dialector struct {
-	*Config
-}
-
-func Open(dsn string) gorm.Dialector {
-	return &Dialector{Config: &Config{DSN: dsn}}
-}
-
-func New(config Config) gorm.Dialector {
-	return &Dialector{Config: &config}
-}
-
-func (dialector Dialector) Name() string {
-	return "hdb"
-}
-
-func (dialector Dialector) Apply(config *gorm.Config) error {
-	if config.NowFunc == nil {
-		if dialector.DefaultDatetimePrecision == nil {
-			var defaultDatetimePrecision = 3
-			dialector.DefaultDatetimePrecision = &defaultDatetimePrecision
-		}
-
-		round := time.Second / time.Duration(math.Pow10(*dialector.DefaultDatetimePrecision))
-		config.NowFunc = func() time.Time { return time.Now().Local().Round(round) }
-	}
-	return nil
-}
-
-func (dialector Dialector) Initialize(db *gorm.DB) (err error) {
-	// ctx := context.Background()
-
-	// register callbacks
-	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{})
-
-	db.Callback().Update().Replace("gorm:update", Update)
-
-	if dialector.DriverName == "" {
-		dialector.DriverName = "hdb"
-	}
-
-	if dialector.DefaultDatetimePrecision == nil {
-		var defaultDatetimePrecision = 3
-		dialector.DefaultDatetimePrecision = &defaultDatetimePrecision
-	}
-
-	if dialector.Conn != nil {
-		db.ConnPool = dialector.Conn
-	} else {
-		db.ConnPool, err = sql.Open(dialector.DriverName, dialector.DSN)
-		if err != nil {
-			return err
-		}
-	}
-
-	// if !dialector.Config.SkipInitializeWithVersion {
-	// 	var version string
-	// 	err = db.ConnPool.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version)
-	// 	if err != nil {
-	// 		return err
-	// 	}
-
-	// 	if strings.Contains(version, "MariaDB") {
-	// 		dialector.Config.DontSupportRenameIndex = true
-	// 		dialector.Config.DontSupportRenameColumn = true
-	// 		dialector.Config.DontSupportForShareClause = true
-	// 	} else if strings.HasPrefix(version, "5.6.") {
-	// 		dialector.Config.DontSupportRenameIndex = true
-	// 		dialector.Config.DontSupportRenameColumn = true
-	// 		dialector.Config.DontSupportForShareClause = true
-	// 	} else if strings.HasPrefix(version, "5.7.") {
-	// 		dialector.Config.DontSupportRenameColumn = true
-	// 		dialector.Config.DontSupportForShareClause = true
-	// 	} else if strings.HasPrefix(version, "5.") {
-	dialector.Config.DisableDatetimePrecision = true
-	dialector.Config.DontSupportRenameIndex = true
-	dialector.Config.DontSupportRenameColumn = true
-	dialector.Config.DontSupportForShareClause = true
-	// 	}
-	// }
-
-	for k, v := range dialector.ClauseBuilders() {
-		db.ClauseBuilders[k] = v
-	}
-	return
-}
-
-func (dialector Dialector) ClauseBuilders() map[string]clause.ClauseBuilder {
-	clauseBuilders := map[string]clause.ClauseBuilder{
-		"ON CONFLICT": func(c clause.Clause, builder clause.Builder) {
-			if onConflict, ok := c.Expression.(clause.OnConflict); ok {
-				builder.WriteString("ON DUPLICATE KEY UPDATE ")
-				if len(onConflict.DoUpdates) == 0 {
-					if s := builder.(*gorm.Statement).Schema; s != nil {
-						var column clause.Column
-						onConflict.DoNothing = false
-
-						if s.PrioritizedPrimaryField != nil {
-							column = clause.Column{Name: s.PrioritizedPrimaryField.DBName}
-						} else if len(s.DBNames) > 0 {
-							column = clause.Column{Name: s.DBNames[0]}
-						}
-
-						if column.Name != "" {
-							onConflict.DoUpdates = []clause.Assignment{{Column: column, Value: column}}
-						}
-					}
-				}
-
-				for idx, assignment := range onConflict.DoUpdates {
-					if idx > 0 {
-						builder.WriteByte(',')
-					}
-
-					builder.WriteQuoted(assignment.Column)
-					builder.WriteByte('=')
-					if column, ok := assignment.Value.(clause.Column); ok && column.Table == "excluded" {
-						column.Table = ""
-						builder.WriteString("VALUES(")
-						builder.WriteQuoted(column)
-						builder.WriteByte(')')
-					} else {
-						builder.AddVar(builder, assignment.Value)
-					}
-				}
-			} else {
-				c.Build(builder)
-			}
-		},
-		"VALUES": func(c clause.Clause, builder clause.Builder) {
-			if values, ok := c.Expression.(clause.Values); ok && len(values.Columns) == 0 {
-				builder.WriteString("VALUES()")
-				return
-			}
-			c.Build(builder)
-		},
-	}
-
-	if dialector.Config.DontSupportForShareClause {
-		clauseBuilders["FOR"] = func(c clause.Clause, builder clause.Builder) {
-			if values, ok := c.Expression.(clause.Locking); ok && strings.EqualFold(values.Strength, "SHARE") {
-				builder.WriteString("LOCK IN SHARE MODE")
-				return
-			}
-			c.Build(builder)
-		}
-	}
-
-	return clauseBuilders
-}
-
-func (dialector Dialector) DefaultValueOf(field *schema.Field) clause.Expression {
-	return clause.Expr{SQL: "DEFAULT"}
-}
-
-func (dialector Dialector) Migrator(db *gorm.DB) gorm.Migrator {
-	return Migrator{
-		Migrator: migrator.Migrator{
-			Config: migrator.Config{
-				DB:        db,
-				Dialector: dialector,
-			},
-		},
-		Dialector: dialector,
-	}
-}
-
-func (dialector Dialector) BindVarTo(writer clause.Writer, stmt *gorm.Statement, v interface{}) {
-	writer.WriteByte('?')
-}
-
-func (dialector Dialector) QuoteTo(writer clause.Writer, str string) {
-	writer.WriteByte('"')
-	if strings.Contains(str, ".") {
-		for idx, str := range strings.Split(str, ".") {
-			if idx > 0 {
-				writer.WriteString(".\"")
-			}
-			writer.WriteString(str)
-			writer.WriteByte('"')
-		}
-	} else {
-		writer.WriteString(str)
-		writer.WriteByte('"')
-	}
-}
-
-func (dialector Dialector) Explain(sql string, vars ...interface{}) string {
-	return logger.ExplainSQL(sql, nil, `'`, vars...)
-}
-
-func (dialector Dialector) DataTypeOf(field *schema.Field) string {
-	switch field.DataType {
-	case schema.Bool:
-		return "BOOLEAN"
-	case schema.Int, schema.Uint:
-		sqlType := "BIGINT"
-		switch {
-		case field.Size <= 8:
-			sqlType = "TINYINT"
-		case field.Size <= 16:
-			sqlType = "SMALLINT"
-		case field.Size <= 32:
-			sqlType = "INTEGER"
-		}
-
-		if field.DataType == schema.Uint {
-			sqlType += " unsigned"
-		}
-
-		if field.AutoIncrement {
-			sqlType += " AUTO_INCREMENT"
-		}
-		return sqlType
-	case schema.Float:
-		if field.Precision > 0 {
-			return fmt.Sprintf("DECIMAL(%d, %d)", field.Precision, field.Scale)
-		}
-
-		if field.Size <= 32 {
-			return "REAL"
-		}
-		return "DOUBLE"
-	case schema.String:
-		size := field.Size
-		defaultSize := dialector.DefaultStringSize
-
-		if size == 0 {
-			if defaultSize > 0 {
-				size = int(defaultSize)
-			} else {
-				hasIndex := field.TagSettings["INDEX"] != "" || field.TagSettings["UNIQUE"] != ""
-				// TEXT, GEOMETRY or JSON column can't have a default value
-				if field.PrimaryKey || field.HasDefaultValue || hasIndex {
-					size = 191 // utf8mb4
-				}
-			}
-		}
-
-		if size >= 65536 && size <= int(math.Pow(2, 24)) {
-			return "mediumtext"
-		} else if size > int(math.Pow(2, 24)) || size <= 0 {
-			return "SHORTTEXT"
-		}
-		return fmt.Sprintf("VARCHAR(%d)", size)
-	case schema.Time:
-		precision := ""
-
-		if !dialector.DisableDatetimePrecision && field.Precision == 0 {
-			field.Precision = *dialector.DefaultDatetimePrecision
-		}
-
-		if field.Precision > 0 {
-			precision = fmt.Sprintf("(%d)", field.Precision)
-		}
-
-		if field.NotNull || field.PrimaryKey {
-			return "SECONDDATE" + precision
-		}
-		return "SECONDDATE" + precision + " NULL"
-	case schema.Bytes:
-		if field.Size > 0 && field.Size < 65536 {
-			return fmt.Sprintf("VARBINARY(%d)", field.Size)
-		}
-
-		return "BLOB"
-	}
-
-	return string(field.DataType)
-}
-
-func (dialectopr Dialector) SavePoint(tx *gorm.DB, name string) error {
-	tx.Exec("SAVEPOINT " + name)
-	return nil
-}
-
-func (dialectopr Dialector) RollbackTo(tx *gorm.DB, name string) error {
-	tx.Exec("ROLLBACK TO SAVEPOINT " + name)
-	return nil
-}
+package hdb
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/SAP/go-hdb/driver"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+type Config struct {
+	DriverName                  string
+	DSN                         string
+	Conn                        gorm.ConnPool
+	Connector                   driver.Connector
+	Host                        string
+	Port                        int
+	Hosts                       []string
+	User                        string
+	Password                    string
+	RefreshPassword             func() (password string, ok bool)
+	Token                       string
+	RefreshToken                func() (token string, ok bool)
+	ClientCert                  []byte
+	ClientKey                   []byte
+	ClientCertFile              string
+	ClientKeyFile               string
+	RefreshClientCert           func() (clientCert, clientKey []byte, ok bool)
+	DatabaseName                string
+	DefaultSchema               string
+	TLSServerName               string
+	TLSInsecureSkipVerify       bool
+	TLSRootCAFiles              []string
+	TLSConfig                   *tls.Config
+	Timeout                     time.Duration
+	PingInterval                time.Duration
+	TCPKeepAlive                time.Duration
+	SessionVariables            map[string]string
+	QueryTimeout                time.Duration
+	OnConnect                   []string
+	ConnectHook                 func(ctx context.Context, conn driver.Conn) error
+	RetryPolicy                 *RetryPolicy
+	SkipInitializeWithVersion   bool
+	DefaultStringSize           uint
+	DefaultDatetimePrecision    *int
+	DisableDatetimePrecision    bool
+	DontSupportRenameIndex      bool
+	DontSupportForShareClause   bool
+	DontSupportIdentity         bool
+	DontSupportNSE              bool
+	DontSupportJSONTable        bool
+	DontSupportReplaceView      bool
+	DontSupportReplaceProcedure bool
+	ServerVersion               string
+	NumberedBindVars            bool
+	ReadOnly                    bool
+	PreserveCase                bool
+	RejectReservedWords         bool
+	UseVarchar                  bool
+	UseTinyintBool              bool
+	UUIDFormat                  UUIDFormat
+	CheckJSON                   bool
+	TimeLocation                *time.Location
+	CheckUintOverflow           bool
+	InListThreshold             int
+	ZeroTimeAsNull              bool
+	EmptyStringAsNull           bool
+	DropTableRestrict           bool
+	TraceDDL                    bool
+	Grants                      []Grant
+}
+
+type Dialector struct {
+	*Config
+
+	// dsnErr is the error parsing the DSN of Open, returned by Initialize.
+	dsnErr error
+}
+
+func Open(dsn string) gorm.Dialector {
+	config, err := ParseDSN(dsn)
+	if err != nil {
+		return &Dialector{Config: &Config{DSN: dsn}, dsnErr: err}
+	}
+	return &Dialector{Config: config}
+}
+
+func New(config Config) gorm.Dialector {
+	return &Dialector{Config: &config}
+}
+
+// OpenReplica opens a read-only dialector for a read-enabled secondary, to be registered
+// as a gorm.io/plugin/dbresolver replica. The DSN addresses the SQL port of the secondary
+// itself: go-hdb doesn't follow HANA's hint-based statement routing, so statements sent to
+// the primary with a RESULT_LAG hint run on the primary.
+func OpenReplica(dsn string) gorm.Dialector {
+	dialector := Open(dsn).(*Dialector)
+	dialector.ReadOnly = true
+	return dialector
+}
+
+// NewReplica is like OpenReplica for a Config.
+func NewReplica(config Config) gorm.Dialector {
+	config.ReadOnly = true
+	return New(config)
+}
+
+func (dialector Dialector) Name() string {
+	return "hdb"
+}
+
+func (dialector Dialector) Apply(config *gorm.Config) error {
+	if config.NowFunc == nil {
+		if dialector.DefaultDatetimePrecision == nil {
+			var defaultDatetimePrecision = 3
+			dialector.DefaultDatetimePrecision = &defaultDatetimePrecision
+		}
+
+		round := time.Second / time.Duration(math.Pow10(*dialector.DefaultDatetimePrecision))
+		config.NowFunc = func() time.Time { return time.Now().Local().Round(round) }
+	}
+	return nil
+}
+
+func (dialector Dialector) Initialize(db *gorm.DB) (err error) {
+	if dialector.dsnErr != nil {
+		return dialector.dsnErr
+	}
+
+	ctx := context.Background()
+
+	// register callbacks
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{})
+
+	db.Callback().Create().Replace("gorm:create", Create)
+	db.Callback().Query().Replace("gorm:query", Query)
+	db.Callback().Update().Replace("gorm:update", Update)
+
+	if err = db.Callback().Create().Before("gorm:create").Register("hdb:create_uuid", createUUID); err != nil {
+		return err
+	}
+
+	if err = db.Callback().Create().Before("gorm:create").Register("hdb:create_sequence_values", createSequenceValues); err != nil {
+		return err
+	}
+
+	if err = db.Callback().Create().Before("gorm:create").Register("hdb:omit_generated", omitGenerated); err != nil {
+		return err
+	}
+
+	if err = db.Callback().Update().Before("gorm:update").Register("hdb:omit_generated", omitGenerated); err != nil {
+		return err
+	}
+
+	if dialector.QueryTimeout > 0 {
+		if err = dialector.registerQueryTimeout(db); err != nil {
+			return err
+		}
+	}
+
+	if dialector.DriverName == "" {
+		dialector.DriverName = "hdb"
+	}
+
+	if dialector.DefaultDatetimePrecision == nil {
+		var defaultDatetimePrecision = 3
+		dialector.DefaultDatetimePrecision = &defaultDatetimePrecision
+	}
+
+	if dialector.Conn != nil {
+		db.ConnPool = dialector.Conn
+	} else {
+		db.ConnPool, err = dialector.openDB()
+		if err != nil {
+			return err
+		}
+
+		if dialector.Connector == nil && dialector.DatabaseName != "" {
+			if err = dialector.resolveDatabase(db); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !dialector.Config.SkipInitializeWithVersion {
+		err = db.ConnPool.QueryRowContext(ctx, "SELECT VERSION FROM SYS.M_DATABASE").Scan(&dialector.Config.ServerVersion)
+		if err != nil {
+			return err
+		}
+
+		dialector.Config.applyVersion()
+	}
+
+	// HANA datetime types carry a fixed precision
+	dialector.Config.DisableDatetimePrecision = true
+
+	for k, v := range dialector.ClauseBuilders() {
+		db.ClauseBuilders[k] = v
+	}
+	return
+}
+
+func (dialector Dialector) ClauseBuilders() map[string]clause.ClauseBuilder {
+	clauseBuilders := map[string]clause.ClauseBuilder{
+		"ON CONFLICT": func(c clause.Clause, builder clause.Builder) {
+			if onConflict, ok := c.Expression.(clause.OnConflict); ok {
+				builder.WriteString("ON DUPLICATE KEY UPDATE ")
+				if len(onConflict.DoUpdates) == 0 {
+					if s := builder.(*gorm.Statement).Schema; s != nil {
+						var column clause.Column
+						onConflict.DoNothing = false
+
+						if s.PrioritizedPrimaryField != nil {
+							column = clause.Column{Name: s.PrioritizedPrimaryField.DBName}
+						} else if len(s.DBNames) > 0 {
+							column = clause.Column{Name: s.DBNames[0]}
+						}
+
+						if column.Name != "" {
+							onConflict.DoUpdates = []clause.Assignment{{Column: column, Value: column}}
+						}
+					}
+				}
+
+				for idx, assignment := range onConflict.DoUpdates {
+					if idx > 0 {
+						builder.WriteByte(',')
+					}
+
+					builder.WriteQuoted(assignment.Column)
+					builder.WriteByte('=')
+					if column, ok := assignment.Value.(clause.Column); ok && column.Table == "excluded" {
+						column.Table = ""
+						builder.WriteString("VALUES(")
+						builder.WriteQuoted(column)
+						builder.WriteByte(')')
+					} else {
+						builder.AddVar(builder, assignment.Value)
+					}
+				}
+			} else {
+				c.Build(builder)
+			}
+		},
+		"VALUES": func(c clause.Clause, builder clause.Builder) {
+			if values, ok := c.Expression.(clause.Values); ok && len(values.Columns) == 0 {
+				builder.WriteString("VALUES()")
+				return
+			}
+			c.Build(builder)
+		},
+	}
+
+	if !dialector.Config.DontSupportJSONTable && dialector.InListThreshold > 0 {
+		clauseBuilders["WHERE"] = func(c clause.Clause, builder clause.Builder) {
+			if where, ok := c.Expression.(clause.Where); ok {
+				c.Expression = clause.Where{Exprs: dialector.inLists(where.Exprs)}
+			}
+			c.Build(builder)
+		}
+	}
+
+	if dialector.Config.DontSupportForShareClause {
+		// HANA before 2.0 SPS 03 has no shared row locks, queries read without them
+		clauseBuilders["FOR"] = func(c clause.Clause, builder clause.Builder) {
+			if values, ok := c.Expression.(clause.Locking); ok && strings.EqualFold(values.Strength, "SHARE") {
+				return
+			}
+			c.Build(builder)
+		}
+	}
+
+	return clauseBuilders
+}
+
+func (dialector Dialector) DefaultValueOf(field *schema.Field) clause.Expression {
+	return clause.Expr{SQL: "DEFAULT"}
+}
+
+func (dialector Dialector) Migrator(db *gorm.DB) gorm.Migrator {
+	if dialector.TraceDDL {
+		if _, ok := db.Logger.(ddlLogger); !ok {
+			db = db.Session(&gorm.Session{Logger: newDDLLogger(db.Logger)})
+		}
+	}
+
+	return Migrator{
+		Migrator: migrator.Migrator{
+			Config: migrator.Config{
+				DB:        db,
+				Dialector: dialector,
+			},
+		},
+		Dialector: dialector,
+	}
+}
+
+func (dialector Dialector) BindVarTo(writer clause.Writer, stmt *gorm.Statement, v interface{}) {
+	if dialector.NumberedBindVars {
+		writer.WriteByte(':')
+		writer.WriteString(strconv.Itoa(len(stmt.Vars)))
+		return
+	}
+	writer.WriteByte('?')
+}
+
+func (dialector Dialector) QuoteTo(writer clause.Writer, str string) {
+	for idx, name := range strings.Split(str, ".") {
+		if idx > 0 {
+			writer.WriteByte('.')
+		}
+
+		if name == "*" {
+			writer.WriteByte('*')
+			continue
+		}
+
+		writer.WriteByte('"')
+		writer.WriteString(strings.ReplaceAll(dialector.NormalizeIdentifier(name), `"`, `""`))
+		writer.WriteByte('"')
+	}
+}
+
+// NormalizeIdentifier returns the name HANA stores for an identifier, which is upper case
+// unless PreserveCase is set.
+func (dialector Dialector) NormalizeIdentifier(name string) string {
+	if dialector.PreserveCase {
+		return name
+	}
+	return strings.ToUpper(name)
+}
+
+var numericPlaceholder = regexp.MustCompile(`\B:(\d+)`)
+
+func (dialector Dialector) Explain(sql string, vars ...interface{}) string {
+	if dialector.NumberedBindVars {
+		return logger.ExplainSQL(sql, numericPlaceholder, `'`, vars...)
+	}
+	return logger.ExplainSQL(sql, nil, `'`, vars...)
+}
+
+func (dialector Dialector) DataTypeOf(field *schema.Field) string {
+	if dataType, ok := lookupDataType(field.FieldType); ok && field.TagSettings["TYPE"] == "" {
+		return dataType.name
+	}
+
+	if isUUIDField(field) {
+		return dialector.uuidTypeOf(field)
+	}
+
+	if field.TagSettings["TYPE"] == "" {
+		switch field.Serializer.(type) {
+		case DecimalSerializer:
+			return decimalTypeOf(field)
+		case GeoJSONSerializer:
+			return spatialTypeOf(field, "ST_GEOMETRY")
+		case nil:
+			if isDecimalType(field.FieldType) {
+				return decimalTypeOf(field)
+			}
+		}
+	}
+
+	switch field.DataType {
+	case schema.Bool:
+		if dialector.UseTinyintBool {
+			return "TINYINT"
+		}
+		return "BOOLEAN"
+	case schema.Int, schema.Uint:
+		// TINYINT is unsigned, the wider integer types are signed only
+		sqlType := "BIGINT"
+		if field.DataType == schema.Uint {
+			switch {
+			case field.Size <= 8:
+				sqlType = "TINYINT"
+			case field.Size <= 16:
+				sqlType = "INTEGER"
+			}
+		} else {
+			switch {
+			case field.Size <= 16:
+				sqlType = "SMALLINT"
+			case field.Size <= 32:
+				sqlType = "INTEGER"
+			}
+		}
+
+		if field.AutoIncrement && !dialector.DontSupportIdentity && sequenceOf(field) == "" {
+			sqlType += " " + identityOf(field)
+		}
+		return sqlType
+	case schema.Float:
+		if field.Precision > 0 {
+			return decimalTypeOf(field)
+		}
+
+		if field.Size <= 32 {
+			return "REAL"
+		}
+		return "DOUBLE"
+	case schema.String:
+		size := field.Size
+		if values := enumValues(field); size == 0 && len(values) > 0 {
+			size = enumSize(values)
+		}
+		if size == 0 {
+			size = int(dialector.defaultStringSizeOf(field))
+		}
+
+		if dialector.isVarchar(field) {
+			if size > maxVarcharSize {
+				return "CLOB"
+			}
+			return fmt.Sprintf("VARCHAR(%d)", size)
+		}
+
+		if size > maxVarcharSize {
+			return "NCLOB"
+		}
+		return fmt.Sprintf("NVARCHAR(%d)", size)
+	case schema.Time:
+		// gorm takes type:time, as of datatypes.Time, for its own time data type
+		switch typ := strings.ToUpper(field.TagSettings["TYPE"]); typ {
+		case "TIME", "DATE", "SECONDDATE":
+			return typ
+		}
+		// TIMESTAMP has a fixed precision of 7 fractional digits, SECONDDATE none
+		if _, ok := field.TagSettings["PRECISION"]; ok && field.Precision == 0 {
+			return "SECONDDATE"
+		}
+		return "TIMESTAMP"
+	case schema.Bytes:
+		return binaryTypeOf(field, "VARBINARY")
+	}
+
+	switch strings.ToUpper(string(field.DataType)) {
+	case "JSON":
+		return "NCLOB"
+	case "DATE", "TIME", "SECONDDATE", "TIMESTAMP":
+		return strings.ToUpper(string(field.DataType))
+	// HANA creates full-text indexes for TEXT, SHORTTEXT and BINTEXT columns implicitly,
+	// HANA Cloud does not support these types and stores the plain column type instead
+	case "TEXT":
+		if dialector.IsCloud() {
+			return "NCLOB"
+		}
+		return "TEXT"
+	case "SHORTTEXT":
+		size := field.Size
+		if size <= 0 || size > maxVarcharSize {
+			size = int(dialector.defaultStringSizeOf(field))
+		}
+
+		if dialector.IsCloud() {
+			return fmt.Sprintf("NVARCHAR(%d)", size)
+		}
+		return fmt.Sprintf("SHORTTEXT(%d)", size)
+	case "BINTEXT":
+		if dialector.IsCloud() {
+			return "BLOB"
+		}
+		return "BINTEXT"
+	case "ALPHANUM":
+		size := field.Size
+		if size <= 0 || size > maxAlphanumSize {
+			size = maxAlphanumSize
+		}
+		return fmt.Sprintf("ALPHANUM(%d)", size)
+	case "ST_GEOMETRY", "ST_POINT":
+		return spatialTypeOf(field, strings.ToUpper(string(field.DataType)))
+	case "DECIMAL":
+		return decimalTypeOf(field)
+	case "SMALLDECIMAL":
+		return "SMALLDECIMAL"
+	case "VARBINARY", "BINARY":
+		return binaryTypeOf(field, strings.ToUpper(string(field.DataType)))
+	case "REAL_VECTOR":
+		if field.Size > 0 {
+			return fmt.Sprintf("REAL_VECTOR(%d)", field.Size)
+		}
+		return "REAL_VECTOR"
+	}
+	return string(field.DataType)
+}
+
+// binaryTypeOf returns the binary type sized by the field's size tag, which is a BLOB for
+// sizes beyond VARBINARY's maximum or without a size, as HANA defaults to a length of 1.
+func binaryTypeOf(field *schema.Field, typeName string) string {
+	if field.Size > 0 && field.Size <= maxVarcharSize {
+		return fmt.Sprintf("%s(%d)", typeName, field.Size)
+	}
+	return "BLOB"
+}
+
+// spatialTypeOf returns the spatial type with the spatial reference system of the field's
+// srid tag.
+func spatialTypeOf(field *schema.Field, typeName string) string {
+	if srid := field.TagSettings["SRID"]; srid != "" {
+		return fmt.Sprintf("%s(%s)", typeName, srid)
+	}
+	return typeName
+}
+
+// maxAlphanumSize is the maximum length of a HANA ALPHANUM column.
+const maxAlphanumSize = 127
+
+// maxDecimalPrecision is the maximum precision of HANA DECIMAL columns.
+const maxDecimalPrecision = 38
+
+// decimalTypeOf returns the DECIMAL type for the precision and scale tags of a field, which
+// is a floating point decimal without them.
+func decimalTypeOf(field *schema.Field) string {
+	switch {
+	case field.Precision > 0:
+		return fmt.Sprintf("DECIMAL(%d, %d)", field.Precision, field.Scale)
+	case field.Scale > 0:
+		return fmt.Sprintf("DECIMAL(%d, %d)", maxDecimalPrecision, field.Scale)
+	}
+	return "DECIMAL"
+}
+
+// identityOf returns the identity clause of an auto increment field, generated always with
+// an identity:always tag and starting at its autoIncrementStart tag.
+func identityOf(field *schema.Field) string {
+	identity := "GENERATED BY DEFAULT AS IDENTITY"
+	if strings.EqualFold(field.TagSettings["IDENTITY"], "ALWAYS") {
+		identity = "GENERATED ALWAYS AS IDENTITY"
+	}
+
+	var options []string
+	if start, err := strconv.ParseInt(field.TagSettings["AUTOINCREMENTSTART"], 10, 64); err == nil {
+		options = append(options, "START WITH "+strconv.FormatInt(start, 10))
+	}
+	if field.AutoIncrementIncrement > 1 {
+		options = append(options, "INCREMENT BY "+strconv.FormatInt(field.AutoIncrementIncrement, 10))
+	}
+
+	if len(options) > 0 {
+		identity += " (" + strings.Join(options, " ") + ")"
+	}
+	return identity
+}
+
+// isVarchar reports whether a string field is stored as single-byte VARCHAR, as set by its
+// varchar or nvarchar tag or else by UseVarchar.
+func (dialector Dialector) isVarchar(field *schema.Field) bool {
+	if _, ok := field.TagSettings["NVARCHAR"]; ok {
+		return false
+	}
+	if _, ok := field.TagSettings["VARCHAR"]; ok {
+		return true
+	}
+	return dialector.UseVarchar
+}
+
+// DefaultStringSizer overrides the dialector's DefaultStringSize for the string fields of a model.
+type DefaultStringSizer interface {
+	DefaultStringSize() uint
+}
+
+// maxVarcharSize is the maximum length of HANA NVARCHAR and VARBINARY columns, longer
+// values are stored as LOBs.
+const maxVarcharSize = 5000
+
+func (dialector Dialector) defaultStringSizeOf(field *schema.Field) uint {
+	if field.Schema != nil {
+		if sizer, ok := reflect.New(field.Schema.ModelType).Interface().(DefaultStringSizer); ok && sizer.DefaultStringSize() > 0 {
+			return sizer.DefaultStringSize()
+		}
+	}
+
+	if dialector.DefaultStringSize > 0 {
+		return dialector.DefaultStringSize
+	}
+	return maxVarcharSize
+}
+
+func (dialectopr Dialector) SavePoint(tx *gorm.DB, name string) error {
+	tx.Exec("SAVEPOINT " + name)
+	return nil
+}
+
+func (dialectopr Dialector) RollbackTo(tx *gorm.DB, name string) error {
+	tx.Exec("ROLLBACK TO SAVEPOINT " + name)
+	return nil
+}
@@ -0,0 +1,228 @@
+package hdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+var errNoServer = errors.New("no server in unit tests")
+
+// noServer is a connector failing to connect, for statements built in dry runs.
+type noServer struct{}
+
+func (noServer) Connect(context.Context) (driver.Conn, error) { return nil, errNoServer }
+func (noServer) Driver() driver.Driver                        { return nil }
+
+// openNoServer opens a session with config on a connector without server, whose
+// catalog queries fail.
+func openNoServer(t *testing.T, config Config, gormConfig *gorm.Config) *gorm.DB {
+	t.Helper()
+
+	config.Conn = sql.OpenDB(noServer{})
+	config.SkipInitializeWithVersion = true
+	gormConfig.DisableAutomaticPing = true
+	gormConfig.Logger = logger.Discard
+	db, err := gorm.Open(New(config), gormConfig)
+	if err != nil {
+		t.Fatalf("failed to open session: %v", err)
+	}
+	return db
+}
+
+// dryRunDB opens a dry run session with config, which builds statements without a server.
+func dryRunDB(t *testing.T, config Config) *gorm.DB {
+	t.Helper()
+	return openNoServer(t, config, &gorm.Config{DryRun: true})
+}
+
+type Typed struct {
+	ID        uint64
+	Small     int16
+	Int       int32
+	Flag      bool
+	Tiny      uint8
+	Short     uint16
+	Word      uint32
+	Ratio     float32
+	Double    float64
+	Amount    float64             `gorm:"precision:10;scale:2"`
+	Price     decimal.Decimal     `gorm:"precision:15;scale:2"`
+	Discount  decimal.NullDecimal `gorm:"scale:4"`
+	Balance   *decimal.Decimal
+	Fraction  big.Rat  `gorm:"type:decimal;precision:20;scale:10"`
+	Quota     *big.Rat `gorm:"type:decimal"`
+	Name      string
+	Code      string `gorm:"size:10"`
+	Body      string `gorm:"size:6000"`
+	Key       string `gorm:"size:20;varchar"`
+	Title     string `gorm:"size:20;nvarchar"`
+	Text      string `gorm:"type:text"`
+	Status    string `gorm:"enum:'OPEN','CLOSED'"`
+	Hash      []byte `gorm:"size:32"`
+	Salt      []byte `gorm:"type:binary;size:16"`
+	Raw       []byte `gorm:"type:varbinary"`
+	Embedding Vector `gorm:"size:3"`
+	Blob      []byte
+	At        time.Time
+	AtSecond  time.Time `gorm:"precision:0"`
+	Day       time.Time `gorm:"type:date"`
+	Seconds   time.Time `gorm:"type:seconddate"`
+	TimeOfDay time.Time `gorm:"type:time"`
+	Serial    int64     `gorm:"autoIncrement"`
+	Counter   int64     `gorm:"autoIncrement;identity:always;autoIncrementStart:100;autoIncrementIncrement:10"`
+}
+
+func TestDataTypeOf(t *testing.T) {
+	typed, err := schema.Parse(&Typed{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse Typed: %v", err)
+	}
+
+	tests := []struct {
+		field    string
+		config   Config
+		dataType string
+	}{
+		{field: "ID", dataType: "BIGINT GENERATED BY DEFAULT AS IDENTITY"},
+		{field: "Small", dataType: "SMALLINT"},
+		{field: "Int", dataType: "INTEGER"},
+		{field: "Flag", dataType: "BOOLEAN"},
+		{field: "Flag", config: Config{UseTinyintBool: true}, dataType: "TINYINT"},
+		{field: "Tiny", dataType: "TINYINT"},
+		{field: "Short", dataType: "INTEGER"},
+		{field: "Word", dataType: "BIGINT"},
+		{field: "Ratio", dataType: "REAL"},
+		{field: "Double", dataType: "DOUBLE"},
+		{field: "Amount", dataType: "DECIMAL(10, 2)"},
+		{field: "Price", dataType: "DECIMAL(15, 2)"},
+		{field: "Discount", dataType: "DECIMAL(38, 4)"},
+		{field: "Balance", dataType: "DECIMAL"},
+		{field: "Fraction", dataType: "DECIMAL(20, 10)"},
+		{field: "Quota", dataType: "DECIMAL"},
+		{field: "Name", dataType: "NVARCHAR(5000)"},
+		{field: "Name", config: Config{DefaultStringSize: 100}, dataType: "NVARCHAR(100)"},
+		{field: "Code", dataType: "NVARCHAR(10)"},
+		{field: "Name", config: Config{UseVarchar: true}, dataType: "VARCHAR(5000)"},
+		{field: "Body", dataType: "NCLOB"},
+		{field: "Body", config: Config{UseVarchar: true}, dataType: "CLOB"},
+		{field: "Key", dataType: "VARCHAR(20)"},
+		{field: "Title", config: Config{UseVarchar: true}, dataType: "NVARCHAR(20)"},
+		{field: "Text", dataType: "TEXT"},
+		{field: "Status", dataType: "NVARCHAR(6)"},
+		{field: "Text", config: Config{ServerVersion: "4.00.000.00.1700000000"}, dataType: "NCLOB"},
+		{field: "Hash", dataType: "VARBINARY(32)"},
+		{field: "Salt", dataType: "BINARY(16)"},
+		{field: "Raw", dataType: "BLOB"},
+		{field: "Embedding", dataType: "REAL_VECTOR(3)"},
+		{field: "Blob", dataType: "BLOB"},
+		{field: "At", dataType: "TIMESTAMP"},
+		{field: "AtSecond", dataType: "SECONDDATE"},
+		{field: "Day", dataType: "DATE"},
+		{field: "Seconds", dataType: "SECONDDATE"},
+		{field: "TimeOfDay", dataType: "TIME"},
+		{field: "Serial", dataType: "BIGINT GENERATED BY DEFAULT AS IDENTITY"},
+		{field: "Serial", config: Config{DontSupportIdentity: true}, dataType: "BIGINT"},
+		{field: "Counter", dataType: "BIGINT GENERATED ALWAYS AS IDENTITY (START WITH 100 INCREMENT BY 10)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			config := tt.config
+			if dataType := (Dialector{Config: &config}).DataTypeOf(typed.LookUpField(tt.field)); dataType != tt.dataType {
+				t.Errorf("got %s, want %s", dataType, tt.dataType)
+			}
+		})
+	}
+}
+
+type Member struct {
+	ID   uint
+	Name string
+	Nick string
+}
+
+func TestNumberedBindVars(t *testing.T) {
+	tests := []struct {
+		name     string
+		numbered bool
+		query    func(tx *gorm.DB) *gorm.DB
+		sql      string
+	}{
+		{
+			name:  "question marks",
+			query: func(tx *gorm.DB) *gorm.DB { return tx.Where("NAME = ? AND NICK = ?", "a", "b").Find(&[]Member{}) },
+			sql:   `SELECT * FROM "MEMBERS" WHERE NAME = ? AND NICK = ?`,
+		},
+		{
+			name:     "positional",
+			numbered: true,
+			query:    func(tx *gorm.DB) *gorm.DB { return tx.Where("NAME = ? AND NICK = ?", "a", "b").Find(&[]Member{}) },
+			sql:      `SELECT * FROM "MEMBERS" WHERE NAME = :1 AND NICK = :2`,
+		},
+		{
+			name:     "sql.Named",
+			numbered: true,
+			query: func(tx *gorm.DB) *gorm.DB {
+				return tx.Where("NAME = @name OR NICK = @name", sql.Named("name", "a")).Find(&[]Member{})
+			},
+			sql: `SELECT * FROM "MEMBERS" WHERE NAME = :1 OR NICK = :2`,
+		},
+		{
+			name:     "map",
+			numbered: true,
+			query: func(tx *gorm.DB) *gorm.DB {
+				return tx.Where("NAME = @name AND NICK <> @name", map[string]interface{}{"name": "a"}).Find(&[]Member{})
+			},
+			sql: `SELECT * FROM "MEMBERS" WHERE NAME = :1 AND NICK <> :2`,
+		},
+		{
+			name: "sql.Named with question marks",
+			query: func(tx *gorm.DB) *gorm.DB {
+				return tx.Where("NAME = @name OR NICK = @name", sql.Named("name", "a")).Find(&[]Member{})
+			},
+			sql: `SELECT * FROM "MEMBERS" WHERE NAME = ? OR NICK = ?`,
+		},
+		{
+			name:     "conditions and limit",
+			numbered: true,
+			query:    func(tx *gorm.DB) *gorm.DB { return tx.Where(&Member{Name: "a"}).Limit(1).Find(&[]Member{}) },
+			sql:      `SELECT * FROM "MEMBERS" WHERE "MEMBERS"."NAME" = :1 LIMIT :2`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := tt.query(dryRunDB(t, Config{NumberedBindVars: tt.numbered})).Statement
+			if sql := stmt.SQL.String(); sql != tt.sql {
+				t.Errorf("got %s, want %s", sql, tt.sql)
+			}
+		})
+	}
+}
+
+func TestShareLock(t *testing.T) {
+	db := dryRunDB(t, Config{DontSupportForShareClause: true})
+
+	stmt := db.Clauses(clause.Locking{Strength: clause.LockingStrengthShare}).Find(&[]Member{}).Statement
+	if sql := strings.TrimSpace(stmt.SQL.String()); sql != `SELECT * FROM "MEMBERS"` {
+		t.Errorf("got %s, want the share lock left out", sql)
+	}
+
+	stmt = db.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).Find(&[]Member{}).Statement
+	if sql := stmt.SQL.String(); sql != `SELECT * FROM "MEMBERS" FOR UPDATE` {
+		t.Errorf("got %s, want the update lock kept", sql)
+	}
+}
@@ -0,0 +1,100 @@
+package hdb
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// hdiCredentials are the credentials of an HDI container service binding.
+type hdiCredentials struct {
+	Host        string      `json:"host"`
+	Port        json.Number `json:"port"`
+	User        string      `json:"user"`
+	Password    string      `json:"password"`
+	Schema      string      `json:"schema"`
+	Certificate string      `json:"certificate"`
+}
+
+type hdiService struct {
+	Name        string         `json:"name"`
+	Label       string         `json:"label"`
+	Tags        []string       `json:"tags"`
+	Credentials hdiCredentials `json:"credentials"`
+}
+
+// ParseHDIBinding builds a Config from the HDI container binding named name (or the first
+// hana binding if name is empty) in a VCAP_SERVICES document. The container's runtime schema
+// becomes the DefaultSchema used by queries and the migrator.
+func ParseHDIBinding(vcapServices []byte, name string) (*Config, error) {
+	var services map[string][]hdiService
+	if err := json.Unmarshal(vcapServices, &services); err != nil {
+		return nil, err
+	}
+
+	for _, bindings := range services {
+		for _, binding := range bindings {
+			if (name != "" && binding.Name == name) || (name == "" && binding.isHANA()) {
+				return binding.Credentials.config()
+			}
+		}
+	}
+
+	if name == "" {
+		return nil, errors.New("no hana service binding found")
+	}
+	return nil, fmt.Errorf("no service binding found with name: %s", name)
+}
+
+// OpenHDI opens the HDI container bound as name to the application via VCAP_SERVICES.
+func OpenHDI(name string) (gorm.Dialector, error) {
+	config, err := ParseHDIBinding([]byte(os.Getenv("VCAP_SERVICES")), name)
+	if err != nil {
+		return nil, err
+	}
+	return New(*config), nil
+}
+
+func (s hdiService) isHANA() bool {
+	if s.Label == "hana" || s.Label == "hana-cloud" {
+		return true
+	}
+	for _, tag := range s.Tags {
+		if tag == "hana" {
+			return true
+		}
+	}
+	return false
+}
+
+func (c hdiCredentials) config() (*Config, error) {
+	config := &Config{
+		Host:          c.Host,
+		User:          c.User,
+		Password:      c.Password,
+		DefaultSchema: c.Schema,
+	}
+
+	if c.Port != "" {
+		port, err := strconv.Atoi(c.Port.String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid hdi binding port: %q", c.Port)
+		}
+		config.Port = port
+	}
+
+	if c.Certificate != "" {
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM([]byte(c.Certificate)) {
+			return nil, errors.New("invalid hdi binding certificate")
+		}
+		config.TLSConfig = &tls.Config{ServerName: c.Host, RootCAs: rootCAs}
+	}
+	return config, nil
+}
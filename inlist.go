@@ -0,0 +1,171 @@
+package hdb
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"regexp"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// inLists rewrites IN conditions with more values than InListThreshold to read their values
+// from a single JSON array parameter, as every value would be a parameter otherwise. go-hdb
+// binds no array parameters, and joining a local temporary table takes a session of its own
+// and a round trip to fill it, while JSON_TABLE keeps the list in the statement it belongs to.
+// Only clause.IN conditions and the slices bound to IN ? of SQL conditions are rewritten.
+func (dialector Dialector) inLists(exprs []clause.Expression) []clause.Expression {
+	results := make([]clause.Expression, len(exprs))
+	for idx, expr := range exprs {
+		switch v := expr.(type) {
+		case clause.IN:
+			if query, ok := dialector.inListQueryOf(v.Values); ok {
+				expr = inListCondition{Column: v.Column, Query: query}
+			}
+		case clause.Expr:
+			vars := make([]interface{}, len(v.Vars))
+			copy(vars, v.Vars)
+			for _, i := range inListVars(v.SQL) {
+				if i >= len(vars) {
+					break
+				}
+				if values, ok := sliceValues(vars[i]); ok {
+					if query, ok := dialector.inListQueryOf(values); ok {
+						vars[i] = query
+					}
+				}
+			}
+			v.Vars = vars
+			expr = v
+		case clause.AndConditions:
+			expr = clause.AndConditions{Exprs: dialector.inLists(v.Exprs)}
+		case clause.OrConditions:
+			expr = clause.OrConditions{Exprs: dialector.inLists(v.Exprs)}
+		case clause.NotConditions:
+			expr = clause.NotConditions{Exprs: dialector.inLists(v.Exprs)}
+		case clause.Where:
+			expr = clause.Where{Exprs: dialector.inLists(v.Exprs)}
+		}
+		results[idx] = expr
+	}
+	return results
+}
+
+// inListPlaceholder matches the end of SQL text followed by the placeholder of an IN list.
+var inListPlaceholder = regexp.MustCompile(`(?i)\bIN\s*\(?\s*$`)
+
+// inListVars returns the indexes of the vars of sql bound to IN ? or IN (?), counting the
+// question marks like clause.Expr does.
+func inListVars(sql string) []int {
+	var indexes []int
+	idx := 0
+	for i := 0; i < len(sql); i++ {
+		if sql[i] != '?' {
+			continue
+		}
+		if inListPlaceholder.MatchString(sql[:i]) {
+			indexes = append(indexes, idx)
+		}
+		idx++
+	}
+	return indexes
+}
+
+// sliceValues returns the values of a slice bound as IN list, which byte slices are not.
+func sliceValues(value interface{}) ([]interface{}, bool) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+
+	values := make([]interface{}, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values, true
+}
+
+// inListQueryOf returns the query of a list of values above the threshold that are all
+// numbers or all strings.
+func (dialector Dialector) inListQueryOf(values []interface{}) (inListQuery, bool) {
+	if dialector.InListThreshold <= 0 || len(values) <= dialector.InListThreshold {
+		return inListQuery{}, false
+	}
+
+	var dataType string
+	for _, value := range values {
+		// driver.Valuer types like UUIDs are bound as their values
+		if _, ok := value.(driver.Valuer); ok {
+			return inListQuery{}, false
+		}
+
+		var valueType string
+		switch reflect.ValueOf(value).Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			valueType = "BIGINT"
+		case reflect.Float32, reflect.Float64:
+			valueType = "DOUBLE"
+		case reflect.String:
+			valueType = "NVARCHAR(5000)"
+		default:
+			return inListQuery{}, false
+		}
+
+		if dataType != "" && dataType != valueType {
+			return inListQuery{}, false
+		}
+		dataType = valueType
+	}
+
+	b, err := json.Marshal(values)
+	if err != nil {
+		return inListQuery{}, false
+	}
+	return inListQuery{JSON: string(b), DataType: dataType}, true
+}
+
+// inListQuery selects the values of a JSON array.
+type inListQuery struct {
+	JSON     string
+	DataType string
+}
+
+func (q inListQuery) Build(builder clause.Builder) {
+	// IN ? expands slices into a parenthesized list, IN (?) into the parentheses given
+	enclose := true
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		if sql := stmt.SQL.String(); len(sql) > 0 && sql[len(sql)-1] == '(' {
+			enclose = false
+		}
+	}
+
+	if enclose {
+		builder.WriteByte('(')
+	}
+	builder.WriteString(`SELECT "VALUE" FROM JSON_TABLE(`)
+	builder.AddVar(builder, q.JSON)
+	builder.WriteString(`, '$[*]' COLUMNS ("VALUE" ` + q.DataType + ` PATH '$')) AS "IN_LIST"`)
+	if enclose {
+		builder.WriteByte(')')
+	}
+}
+
+// inListCondition is a column's IN condition with an inListQuery.
+type inListCondition struct {
+	Column interface{}
+	Query  inListQuery
+}
+
+func (c inListCondition) Build(builder clause.Builder) {
+	builder.WriteQuoted(c.Column)
+	builder.WriteString(" IN ")
+	c.Query.Build(builder)
+}
+
+func (c inListCondition) NegationBuild(builder clause.Builder) {
+	builder.WriteQuoted(c.Column)
+	builder.WriteString(" NOT IN ")
+	c.Query.Build(builder)
+}
@@ -0,0 +1,58 @@
+package hdb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInListVars(t *testing.T) {
+	tests := []struct {
+		sql     string
+		indexes []int
+	}{
+		{sql: "ID IN ?", indexes: []int{0}},
+		{sql: "ID in (?)", indexes: []int{0}},
+		{sql: "ID NOT IN ? AND NAME = ?", indexes: []int{0}},
+		{sql: "NAME = ? AND ID IN ?", indexes: []int{1}},
+		{sql: "X = ANY(?) OR Y = ?"},
+		{sql: "JOIN ? ON A.ID = B.ID"},
+		{sql: "BEGIN ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			if indexes := inListVars(tt.sql); !reflect.DeepEqual(indexes, tt.indexes) {
+				t.Errorf("got %v, want %v", indexes, tt.indexes)
+			}
+		})
+	}
+}
+
+func TestInListQueryOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []interface{}
+		query  inListQuery
+		ok     bool
+	}{
+		{name: "numbers", values: []interface{}{1, 2, 3}, query: inListQuery{JSON: "[1,2,3]", DataType: "BIGINT"}, ok: true},
+		{name: "strings", values: []interface{}{"a", "b", "c"}, query: inListQuery{JSON: `["a","b","c"]`, DataType: "NVARCHAR(5000)"}, ok: true},
+		{name: "floats", values: []interface{}{1.5, 2.5, 3.5}, query: inListQuery{JSON: "[1.5,2.5,3.5]", DataType: "DOUBLE"}, ok: true},
+		{name: "mixed", values: []interface{}{1, "b", 3}},
+		{name: "at threshold", values: []interface{}{1, 2}},
+	}
+
+	dialector := Dialector{Config: &Config{InListThreshold: 2}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, ok := dialector.inListQueryOf(tt.values)
+			if ok != tt.ok || query != tt.query {
+				t.Errorf("got %+v %v, want %+v %v", query, ok, tt.query, tt.ok)
+			}
+		})
+	}
+
+	if _, ok := (Dialector{Config: &Config{}}).inListQueryOf([]interface{}{1, 2, 3}); ok {
+		t.Error("got a JSON array without InListThreshold")
+	}
+}
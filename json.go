@@ -0,0 +1,74 @@
+package hdb
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// isJSONField reports whether the field holds JSON documents, like gorm.io/datatypes' JSON,
+// which are stored as NCLOB.
+func isJSONField(field *schema.Field) bool {
+	return strings.EqualFold(string(field.DataType), "json")
+}
+
+// createJSONChecks adds IS JSON check constraints for the JSON fields of the statement's
+// model if CheckJSON is set, or for a single field when names are given.
+func (m Migrator) createJSONChecks(stmt *gorm.Statement, names ...string) error {
+	if !m.CheckJSON || stmt.Schema == nil {
+		return nil
+	}
+
+	for _, field := range stmt.Schema.Fields {
+		if !isJSONField(field) || field.DBName == "" || (len(names) > 0 && field.DBName != names[0]) {
+			continue
+		}
+
+		// suffixed to keep it apart from the constraints of check tags on the column
+		name := m.DB.NamingStrategy.CheckerName(stmt.Table, field.DBName+"_JSON")
+		if err := m.DB.Exec(
+			"ALTER TABLE ? ADD CONSTRAINT ? CHECK (? IS JSON)",
+			m.CurrentTable(stmt), clause.Column{Name: name}, clause.Column{Name: field.DBName},
+		).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JSONPath is a JSON_VALUE or JSON_QUERY expression extracting a path from a JSON column.
+type JSONPath struct {
+	function string
+	column   string
+	path     string
+}
+
+// JSONValue extracts the scalar value at path, e.g. $.address.city, from column.
+//
+//	db.Where(hdb.JSONValue("attributes", "$.color").Equals("red")).Find(&products)
+func JSONValue(column, path string) JSONPath {
+	return JSONPath{function: "JSON_VALUE", column: column, path: path}
+}
+
+// JSONQuery extracts the object or array at path from column.
+func JSONQuery(column, path string) JSONPath {
+	return JSONPath{function: "JSON_QUERY", column: column, path: path}
+}
+
+// Build implements clause.Expression. The path is a literal, as HANA doesn't accept
+// parameters for it.
+func (p JSONPath) Build(builder clause.Builder) {
+	builder.WriteString(p.function)
+	builder.WriteByte('(')
+	builder.WriteQuoted(clause.Column{Name: p.column})
+	builder.WriteString(", ")
+	builder.WriteString(quoteString(p.path))
+	builder.WriteByte(')')
+}
+
+// Equals compares the extracted value with value.
+func (p JSONPath) Equals(value interface{}) clause.Expression {
+	return clause.Expr{SQL: "? = ?", Vars: []interface{}{p, value}}
+}
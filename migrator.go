@@ -2,9 +2,15 @@ package hdb
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+
+	hdbdriver "github.com/SAP/go-hdb/driver"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -17,8 +23,39 @@ type Migrator struct {
 	Dialector
 }
 
+// FullDataTypeOf returns the column definition of a field, which HANA expects with the
+// default value ahead of NOT NULL.
 func (m Migrator) FullDataTypeOf(field *schema.Field) clause.Expr {
-	expr := m.Migrator.FullDataTypeOf(field)
+	return m.columnDefinitionOf(field, false)
+}
+
+// columnDefinitionOf returns the column definition of a field, stating NULL for nullable
+// columns with explicitNull.
+func (m Migrator) columnDefinitionOf(field *schema.Field, explicitNull bool) clause.Expr {
+	expr := clause.Expr{SQL: m.Migrator.DataTypeOf(field)}
+	if expression := generatedOf(field); expression != "" {
+		// generated columns take neither defaults nor NOT NULL
+		expr.SQL += " GENERATED ALWAYS AS (" + expression + ")"
+	} else if systemTime := systemTimeOf(field); systemTime != "" {
+		expr.SQL += " NOT NULL GENERATED ALWAYS AS ROW " + systemTime
+	} else {
+		if value, ok := m.defaultValueOf(field); ok {
+			expr.SQL += " DEFAULT " + value
+		}
+		if field.NotNull {
+			expr.SQL += " NOT NULL"
+		} else if explicitNull && !field.PrimaryKey {
+			expr.SQL += " NULL"
+		}
+	}
+
+	if hasFuzzySearchIndex(field) {
+		expr.SQL += " FUZZY SEARCH INDEX ON"
+	}
+
+	if unit := loadUnitOf(field); unit != "" && !m.DontSupportNSE {
+		expr.SQL += " " + unit.loadable()
+	}
 
 	if value, ok := field.TagSettings["COMMENT"]; ok {
 		expr.SQL += " COMMENT " + m.Dialector.Explain("?", value)
@@ -27,240 +64,1180 @@ func (m Migrator) FullDataTypeOf(field *schema.Field) clause.Expr {
 	return expr
 }
 
+// defaultValueOf returns the default value clause of a field, calling SYSUUID and other
+// functions rather than taking them as the strings gorm parses them as.
+func (m Migrator) defaultValueOf(field *schema.Field) (string, bool) {
+	if hasUUIDDefault(field) {
+		return m.Dialector.uuidDefaultOf(field)
+	}
+	if value, ok := functionDefault(field); ok {
+		return value, true
+	}
+	if !field.HasDefaultValue || field.DefaultValue == "(-)" {
+		return "", false
+	}
+
+	if field.DefaultValueInterface != nil {
+		return m.Dialector.Explain("?", field.DefaultValueInterface), true
+	}
+	return field.DefaultValue, field.DefaultValue != ""
+}
+
+// CreateTable creates the tables of the models in the store they set, after validating
+// their identifiers and creating the sequences backing their fields, and adds IS JSON and
+// enum checks and table comments afterwards.
+func (m Migrator) CreateTable(values ...interface{}) error {
+	for _, value := range m.ReorderModels(values, false) {
+		if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+			if err := m.validateIdentifiers(stmt); err != nil {
+				return err
+			}
+			if err := m.createSequences(stmt); err != nil {
+				return err
+			}
+			if err := m.createTable(stmt, tableTypeOf(stmt)); err != nil {
+				return err
+			}
+			if err := m.createJSONChecks(stmt); err != nil {
+				return err
+			}
+			if err := m.createEnumChecks(stmt); err != nil {
+				return err
+			}
+			return m.createTableComment(stmt)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AlterColumn alters a column to the type, default and nullability of its field.
 func (m Migrator) AlterColumn(value interface{}, field string) error {
+	return m.AlterColumns(value, field)
+}
+
+// AlterColumns alters the columns of several fields in a single statement.
+func (m Migrator) AlterColumns(value interface{}, fields ...string) error {
 	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
-		if field := stmt.Schema.LookUpField(field); field != nil {
-			return m.DB.Exec(
-				"ALTER TABLE ? MODIFY COLUMN ? ?",
-				clause.Table{Name: stmt.Table}, clause.Column{Name: field.DBName}, m.FullDataTypeOf(field),
-			).Error
+		var (
+			sql  strings.Builder
+			vars = []interface{}{m.CurrentTable(stmt)}
+		)
+		sql.WriteString("ALTER TABLE ? ALTER (")
+		for idx, name := range fields {
+			field := stmt.Schema.LookUpField(name)
+			if field == nil {
+				return fmt.Errorf("failed to look up field with name: %s", name)
+			}
+
+			if idx > 0 {
+				sql.WriteString(", ")
+			}
+			sql.WriteString("? ?")
+			vars = append(vars, clause.Column{Name: field.DBName}, m.alterDataTypeOf(field))
 		}
-		return fmt.Errorf("failed to look up field with name: %s", field)
+		sql.WriteByte(')')
+
+		return m.DB.Exec(sql.String(), vars...).Error
 	})
 }
 
-func (m Migrator) RenameColumn(value interface{}, oldName, newName string) error {
+// AddColumn adds the column of a field with ALTER TABLE ... ADD (...) and its IS JSON and
+// enum checks and mask.
+func (m Migrator) AddColumn(value interface{}, name string) error {
 	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
-		if m.Dialector.DontSupportRenameColumn {
-			var field *schema.Field
-			if f := stmt.Schema.LookUpField(oldName); f != nil {
-				oldName = f.DBName
-				field = f
-			}
+		field := stmt.Schema.LookUpField(name)
+		if field == nil {
+			return fmt.Errorf("failed to look up field with name: %s", name)
+		}
+		// HANA adds the dynamic columns of schema-flexible tables as they are written
+		if field.IgnoreMigration || isFlexibleField(field) {
+			return nil
+		}
+		if err := m.validateIdentifier(stmt, field.DBName); err != nil {
+			return err
+		}
 
-			if f := stmt.Schema.LookUpField(newName); f != nil {
-				newName = f.DBName
-				field = f
-			}
+		if err := m.DB.Exec(
+			"ALTER TABLE ? ADD (? ?)",
+			m.CurrentTable(stmt), clause.Column{Name: field.DBName}, m.DB.Migrator().FullDataTypeOf(field),
+		).Error; err != nil {
+			return err
+		}
+
+		if err := m.createJSONChecks(stmt, field.DBName); err != nil {
+			return err
+		}
+		if err := m.createEnumChecks(stmt, field.DBName); err != nil {
+			return err
+		}
+		if mask := maskOf(field); mask != "" {
+			return m.setMask(stmt, field.DBName, mask, false)
+		}
+		return nil
+	})
+}
 
-			if field != nil {
-				return m.DB.Exec(
-					"ALTER TABLE ? CHANGE ? ? ?",
-					clause.Table{Name: stmt.Table}, clause.Column{Name: oldName}, clause.Column{Name: newName}, m.FullDataTypeOf(field),
-				).Error
+// DropColumn drops a column with ALTER TABLE ... DROP (...).
+func (m Migrator) DropColumn(value interface{}, name string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if stmt.Schema != nil {
+			if field := stmt.Schema.LookUpField(name); field != nil {
+				name = field.DBName
 			}
-		} else {
-			return m.Migrator.RenameColumn(value, oldName, newName)
 		}
 
-		return fmt.Errorf("failed to look up field with name: %s", newName)
+		return m.DB.Exec("ALTER TABLE ? DROP (?)", m.CurrentTable(stmt), clause.Column{Name: name}).Error
 	})
 }
 
-func (m Migrator) RenameIndex(value interface{}, oldName, newName string) error {
-	if m.Dialector.DontSupportRenameIndex {
-		return m.RunWithValue(value, func(stmt *gorm.Statement) error {
-			err := m.DropIndex(value, oldName)
-			if err == nil {
-				if idx := stmt.Schema.LookIndex(newName); idx == nil {
-					if idx = stmt.Schema.LookIndex(oldName); idx != nil {
-						opts := m.BuildIndexOptions(idx.Fields, stmt)
-						values := []interface{}{clause.Column{Name: newName}, clause.Table{Name: stmt.Table}, opts}
-
-						createIndexSQL := "CREATE "
-						if idx.Class != "" {
-							createIndexSQL += idx.Class + " "
-						}
-						createIndexSQL += "INDEX ? ON ??"
-
-						if idx.Type != "" {
-							createIndexSQL += " USING " + idx.Type
-						}
-
-						return m.DB.Exec(createIndexSQL, values...).Error
-					}
-				}
+// alterDataTypeOf returns the column definition a column is altered to, which states NULL
+// to drop NOT NULL and leaves out the identity clause, as identities can't be altered.
+func (m Migrator) alterDataTypeOf(field *schema.Field) clause.Expr {
+	alterField := *field
+	alterField.AutoIncrement = false
+	return m.columnDefinitionOf(&alterField, true)
+}
 
-				err = m.CreateIndex(value, newName)
-			}
+func (m Migrator) RenameColumn(value interface{}, oldName, newName string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if field := stmt.Schema.LookUpField(oldName); field != nil {
+			oldName = field.DBName
+		}
+		if field := stmt.Schema.LookUpField(newName); field != nil {
+			newName = field.DBName
+		}
+
+		return m.DB.Exec(
+			"RENAME COLUMN ?.? TO ?",
+			m.CurrentTable(stmt), clause.Column{Name: oldName}, clause.Column{Name: newName},
+		).Error
+	})
+}
+
+// RenameTable renames a table with RENAME TABLE. The tables are models or names, which
+// may be schema qualified, and the table keeps its schema.
+func (m Migrator) RenameTable(oldName, newName interface{}) error {
+	return m.renameTable(oldName, newName, false)
+}
+
+// RenameTableWithSynonyms renames a table like RenameTable and recreates the private and
+// public synonyms for it to refer to the renamed table.
+func (m Migrator) RenameTableWithSynonyms(oldName, newName interface{}) error {
+	return m.renameTable(oldName, newName, true)
+}
+
+func (m Migrator) renameTable(oldName, newName interface{}, withSynonyms bool) error {
+	oldSchema, oldTable, err := m.tableNameOf(oldName)
+	if err != nil {
+		return err
+	}
+	if oldSchema == "" {
+		oldSchema = m.CurrentDatabase()
+	}
+
+	newSchema, newTable, err := m.tableNameOf(newName)
+	if err != nil {
+		return err
+	}
+	if newSchema != "" && newSchema != oldSchema {
+		return fmt.Errorf("failed to rename table %s.%s to schema %s, tables can't be moved between schemas", oldSchema, oldTable, newSchema)
+	}
 
+	var synonyms []synonym
+	if withSynonyms {
+		if synonyms, err = m.synonymsOf(oldSchema, oldTable); err != nil {
 			return err
-		})
-	} else {
-		return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		}
+	}
+
+	if err := m.DB.Exec(
+		"RENAME TABLE ? TO ?", clause.Table{Name: oldSchema + "." + oldTable}, clause.Table{Name: newTable},
+	).Error; err != nil {
+		return err
+	}
+
+	for _, synonym := range synonyms {
+		if err := m.recreateSynonym(synonym, clause.Table{Name: oldSchema + "." + newTable}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tableNameOf returns the schema and name of a table given as model or name, with an empty
+// schema for unqualified names.
+func (m Migrator) tableNameOf(value interface{}) (string, string, error) {
+	name, ok := value.(string)
+	if !ok {
+		stmt := &gorm.Statement{DB: m.DB}
+		if err := stmt.Parse(value); err != nil {
+			return "", "", err
+		}
+		name = m.fullTable(stmt)
+	}
+
+	if !strings.Contains(name, ".") {
+		return "", m.NormalizeIdentifier(strings.Trim(name, `"`)), nil
+	}
+	schemaName, table := m.CurrentSchema(m.DB.Statement, name)
+	return schemaName, table, nil
+}
+
+// RenameIndex renames an index with RENAME INDEX, keeping it in the schema of its table.
+// Servers without RENAME INDEX, as detected from the version, drop the index and create it
+// again with the new name.
+func (m Migrator) RenameIndex(value interface{}, oldName, newName string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		var oldIndex *schema.Index
+		if stmt.Schema != nil {
+			if oldIndex = stmt.Schema.LookIndex(oldName); oldIndex != nil {
+				oldName = oldIndex.Name
+			}
+		}
+
+		if !m.Dialector.DontSupportRenameIndex {
 			return m.DB.Exec(
-				"ALTER TABLE ? RENAME INDEX ? TO ?",
-				clause.Table{Name: stmt.Table}, clause.Column{Name: oldName}, clause.Column{Name: newName},
+				"RENAME INDEX ? TO ?", m.indexName(stmt, oldName), clause.Column{Name: newName},
 			).Error
-		})
-	}
+		}
+
+		if err := m.DropIndex(value, oldName); err != nil {
+			return err
+		}
+		if stmt.Schema != nil && stmt.Schema.LookIndex(newName) == nil && oldIndex != nil {
+			newIndex := *oldIndex
+			newIndex.Name = newName
+			return m.createIndex(stmt, &newIndex)
+		}
+		return m.CreateIndex(value, newName)
+	})
 }
 
+// DropTable drops the existing tables of the models, dependent models first, along with
+// their history tables and the sequences backing their fields. Views and constraints
+// depending on the tables are dropped with them, unless DropTableRestrict is set to fail on
+// such dependencies instead. The views and virtual tables of models implementing Viewer or
+// VirtualTabler are dropped instead.
 func (m Migrator) DropTable(values ...interface{}) error {
+	dropOption := "CASCADE"
+	if m.DropTableRestrict {
+		dropOption = "RESTRICT"
+	}
+
 	values = m.ReorderModels(values, false)
-	tx := m.DB.Session(&gorm.Session{})
-	tx.Exec("SET FOREIGN_KEY_CHECKS = 0;")
 	for i := len(values) - 1; i >= 0; i-- {
+		if view, ok := m.viewOf(values[i]); ok {
+			if err := m.DropView(view.name); err != nil {
+				return err
+			}
+			continue
+		}
+		if m.isVirtualTable(values[i]) {
+			if err := m.DropVirtualTable(values[i]); err != nil {
+				return err
+			}
+			continue
+		}
+		if !m.HasTable(values[i]) {
+			continue
+		}
+
 		if err := m.RunWithValue(values[i], func(stmt *gorm.Statement) error {
-			return tx.Exec("DROP TABLE IF EXISTS ? CASCADE", clause.Table{Name: stmt.Table}).Error
+			if err := m.dropSystemVersioning(stmt); err != nil {
+				return err
+			}
+			if err := m.DB.Exec("DROP TABLE ? "+dropOption, m.CurrentTable(stmt)).Error; err != nil {
+				return err
+			}
+			if err := m.dropHistoryTable(stmt); err != nil {
+				return err
+			}
+			return m.dropSequences(m.DB, stmt)
 		}); err != nil {
 			return err
 		}
 	}
-	tx.Exec("SET FOREIGN_KEY_CHECKS = 1;")
 	return nil
 }
 
+// CreateConstraint adds the foreign key, check or unique constraint name of the model of
+// value with ALTER TABLE ADD CONSTRAINT, on the table in its schema. Checks are left out
+// when the table has a check of the same condition.
+func (m Migrator) CreateConstraint(value interface{}, name string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		constraint, table := m.GuessConstraintInterfaceAndTable(stmt, name)
+		if constraint == nil {
+			return nil
+		}
+		if chk, ok := constraint.(*schema.CheckConstraint); ok {
+			return m.addCheck(stmt, chk)
+		}
+		if foreignKey, ok := constraint.(*schema.Constraint); ok {
+			sql, vars, err := buildForeignKey(foreignKey)
+			if err != nil {
+				return err
+			}
+			return m.DB.Exec("ALTER TABLE ? ADD "+sql, append([]interface{}{m.constraintTable(stmt, table)}, vars...)...).Error
+		}
+		sql, vars := constraint.Build()
+		return m.DB.Exec("ALTER TABLE ? ADD "+sql, append([]interface{}{m.constraintTable(stmt, table)}, vars...)...).Error
+	})
+}
+
+// DropConstraint drops a constraint with DROP CONSTRAINT, or DROP PRIMARY KEY for primary
+// keys. The table is taken from SYS.REFERENTIAL_CONSTRAINTS and SYS.CONSTRAINTS if found,
+// as foreign keys of a relation may be defined on the other table.
 func (m Migrator) DropConstraint(value interface{}, name string) error {
 	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
 		constraint, chk, table := m.GuessConstraintAndTable(stmt, name)
 		if chk != nil {
-			return m.DB.Exec("ALTER TABLE ? DROP CHECK ?", clause.Table{Name: stmt.Table}, clause.Column{Name: chk.Name}).Error
-		}
-		if constraint != nil {
+			name = chk.Name
+		} else if constraint != nil {
 			name = constraint.Name
 		}
 
-		return m.DB.Exec(
-			"ALTER TABLE ? DROP FOREIGN KEY ?", clause.Table{Name: table}, clause.Column{Name: name},
-		).Error
-	})
-}
+		target := m.constraintTable(stmt, table)
+		schemaName, _ := m.CurrentSchema(stmt, m.fullTable(stmt))
 
-// ColumnTypes column types return columnTypes,error
-func (m Migrator) ColumnTypes(value interface{}) (columnTypes []gorm.ColumnType, err error) {
-	columnTypes = make([]gorm.ColumnType, 0)
-	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
 		var (
-			currentDatabase = m.DB.Migrator().CurrentDatabase()
-			table           = stmt.Table
-			columnTypeSQL   = `SELECT
-			                      UPPER(COLUMN_NAME) as column_name
-													, DEFAULT_VALUE as column_default
-													, IS_NULLABLE as is_nullable
-													, DATA_TYPE_NAME as data_type
-													, LENGTH as character_maximum_length
-													, CONCAT(DATA_TYPE_NAME, 
-															CONCAT('(', 
-																	CONCAT(LENGTH, 	
-																			CONCAT( (CASE WHEN SCALE IS NOT NULL THEN CONCAT(',', SCALE) ELSE '' END),
-																					')'
-																			)
-																	)
-															)
-													) as column_type
-													, NULL as column_key
-													, GENERATION_TYPE as extra
-													, COMMENTS as column_comment
-													, LENGTH as numeric_precision
-													, SCALE as numeric_scale
-			`
-			rows, err = m.DB.Session(&gorm.Session{}).Table(table).Limit(1).Rows()
+			constraintTable string
+			isPrimaryKey    string
 		)
-		log.Println("currentDatabase", currentDatabase)
-		log.Println("table", table)
+		if err := m.DB.Raw(
+			"SELECT TABLE_NAME, 'FALSE' FROM SYS.REFERENTIAL_CONSTRAINTS WHERE SCHEMA_NAME = ? AND CONSTRAINT_NAME = ? "+
+				"UNION ALL SELECT TABLE_NAME, IS_PRIMARY_KEY FROM SYS.CONSTRAINTS WHERE SCHEMA_NAME = ? AND CONSTRAINT_NAME = ? LIMIT 1",
+			schemaName, m.NormalizeIdentifier(name), schemaName, m.NormalizeIdentifier(name),
+		).Row().Scan(&constraintTable, &isPrimaryKey); err == nil {
+			target = clause.Table{Name: schemaName + "." + constraintTable}
+		}
 
-		if err != nil {
-			return err
+		if isPrimaryKey == "TRUE" {
+			return m.DB.Exec("ALTER TABLE ? DROP PRIMARY KEY", target).Error
 		}
+		return m.DB.Exec("ALTER TABLE ? DROP CONSTRAINT ?", target, clause.Column{Name: name}).Error
+	})
+}
 
-		rawColumnTypes, err := rows.ColumnTypes()
+// MigrateColumn alters a column when it differs from its field by type, length, precision
+// and scale, nullability, default or comment, as compared by columnChanged, and replaces
+// the check constraints of enum fields whose values changed. Generated columns are left as
+// they are, HANA can't alter them.
+func (m Migrator) MigrateColumn(value interface{}, field *schema.Field, columnType gorm.ColumnType) error {
+	if field.IgnoreMigration || isGeneratedField(field) || isGenerated(columnType) {
+		return nil
+	}
 
-		if err := rows.Close(); err != nil {
+	if m.columnChanged(field, columnType) {
+		if err := m.DB.Migrator().AlterColumn(value, field.DBName); err != nil {
 			return err
 		}
+	}
+
+	if err := m.DB.Migrator().MigrateColumnUnique(value, field, columnType); err != nil {
+		return err
+	}
+	return m.migrateEnumCheck(value, field)
+}
+
+// columnDiff is how a column differs from its field.
+type columnDiff struct {
+	// typeChanged is set for a different type, length, precision or scale.
+	typeChanged bool
+	// narrowed is set for a different type or a shorter length, precision or scale, which
+	// may not hold all values of the column, and for any type change of a primary key.
+	narrowed bool
+	// definitionChanged is set for a different nullability, default or comment.
+	definitionChanged bool
+}
+
+func (d columnDiff) changed() bool {
+	return d.typeChanged || d.definitionChanged
+}
+
+// columnChanged compares a column with its field the way HANA stores columns: types by
+// their name and aliases, lengths and DECIMAL precision and scale as numbers, and defaults
+// by value, so that equivalent definitions aren't altered over and over.
+func (m Migrator) columnChanged(field *schema.Field, columnType gorm.ColumnType) bool {
+	return m.diffColumn(field, columnType).changed()
+}
+
+func (m Migrator) diffColumn(field *schema.Field, columnType gorm.ColumnType) (diff columnDiff) {
+	definition := m.Dialector.DataTypeOf(field)
+	_, args := parseDataType(definition)
+	columnDataType := strings.ToUpper(columnType.DatabaseTypeName())
+
+	if !m.sameType(field, definition, columnDataType) {
+		diff.typeChanged, diff.narrowed = true, true
+	}
+
+	// the arguments of a type are those of its alias the column is stored as
+	if hasLength(columnDataType) && len(args) == 1 {
+		if length, ok := columnType.Length(); ok && length != args[0] {
+			diff.typeChanged = true
+			diff.narrowed = diff.narrowed || args[0] < length
+		}
+	}
+
+	if columnDataType == "DECIMAL" && len(args) > 0 {
+		precision, scale := args[0], int64(0)
+		if len(args) > 1 {
+			scale = args[1]
+		}
+		if currentPrecision, currentScale, ok := columnType.DecimalSize(); ok && (currentPrecision != precision || currentScale != scale) {
+			diff.typeChanged = true
+			diff.narrowed = diff.narrowed || precision < currentPrecision || scale < currentScale
+		}
+	}
+
+	// the foreign keys referencing a primary key keep the type it is altered from
+	if field.PrimaryKey && diff.typeChanged {
+		diff.narrowed = true
+	}
+
+	if nullable, ok := columnType.Nullable(); ok && !field.PrimaryKey && nullable == field.NotNull {
+		diff.definitionChanged = true
+	}
+
+	if !field.PrimaryKey {
+		value, _ := m.defaultValueOf(field)
+		columnDefault, _ := columnType.DefaultValue()
+		if !sameDefaultValue(field, value, columnDefault) {
+			diff.definitionChanged = true
+		}
+
+		if comment, _ := columnType.Comment(); comment != field.Comment {
+			diff.definitionChanged = true
+		}
+	}
+	return diff
+}
+
+// sameType reports whether a column of columnDataType holds a field created with the type
+// definition. BOOLEAN and TINYINT columns both hold bool fields, whichever UseTinyintBool
+// creates, and HANA stores FLOAT(n) as REAL or DOUBLE.
+func (m Migrator) sameType(field *schema.Field, definition, columnDataType string) bool {
+	dataType, args := parseDataType(definition)
+	if dataType == columnDataType {
+		return true
+	}
+	if floatType := floatTypeOf(dataType, args); floatType != "" && floatType == columnDataType {
+		return true
+	}
+	if field.DataType == schema.Bool && isBoolType(columnDataType) {
+		return true
+	}
+	for _, alias := range m.DB.Migrator().GetTypeAliases(columnDataType) {
+		if strings.EqualFold(alias, dataType) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDataType splits a data type like DECIMAL(10, 2) into its upper case name and
+// arguments, leaving out an identity clause.
+func parseDataType(dataType string) (string, []int64) {
+	dataType = strings.ToUpper(strings.TrimSpace(dataType))
+	if idx := strings.Index(dataType, " GENERATED "); idx >= 0 {
+		dataType = dataType[:idx]
+	}
+
+	open := strings.IndexByte(dataType, '(')
+	if open < 0 || !strings.HasSuffix(dataType, ")") {
+		return dataType, nil
+	}
+
+	var args []int64
+	for _, arg := range strings.Split(dataType[open+1:len(dataType)-1], ",") {
+		n, err := strconv.ParseInt(strings.TrimSpace(arg), 10, 64)
+		if err != nil {
+			return strings.TrimSpace(dataType[:open]), nil
+		}
+		args = append(args, n)
+	}
+	return strings.TrimSpace(dataType[:open]), args
+}
 
-		if !m.DisableDatetimePrecision {
-			columnTypeSQL += `, (CASE
-				WHEN DATA_TYPE_NAME = 'DATE' THEN 10
-				WHEN DATA_TYPE_NAME = 'TIME' THEN 12
-				WHEN DATA_TYPE_NAME = 'SECONDDATE' THEN 10
-				WHEN DATA_TYPE_NAME = 'TIMESTAMP' THEN 19
-				ELSE NULL
-				END
-				) as datetime_precision `
+// sameDefaultValue reports whether a column's default as stored in TABLE_COLUMNS is the
+// default value clause of the field. Functions are compared regardless of case and
+// parentheses, numbers and booleans by value.
+func sameDefaultValue(field *schema.Field, value, columnDefault string) bool {
+	value, columnDefault = normalizeDefault(value), normalizeDefault(columnDefault)
+	if value == columnDefault {
+		return true
+	}
+	if value == "" || columnDefault == "" {
+		return false
+	}
+
+	if _, ok := functionDefault(field); ok || hasUUIDDefault(field) {
+		return sameDefault(columnDefault, value)
+	}
+
+	switch field.DataType {
+	case schema.Bool:
+		v1, err1 := strconv.ParseBool(value)
+		v2, err2 := strconv.ParseBool(columnDefault)
+		return err1 == nil && err2 == nil && v1 == v2
+	case schema.Int, schema.Uint, schema.Float:
+		v1, err1 := strconv.ParseFloat(value, 64)
+		v2, err2 := strconv.ParseFloat(columnDefault, 64)
+		return err1 == nil && err2 == nil && v1 == v2
+	}
+	return false
+}
+
+// normalizeDefault returns a default value without the quotes of string literals, and
+// empty for NULL.
+func normalizeDefault(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "NULL") {
+		return ""
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+	return value
+}
+
+func isBoolType(databaseTypeName string) bool {
+	return strings.EqualFold(databaseTypeName, "BOOLEAN") || strings.EqualFold(databaseTypeName, "TINYINT")
+}
+
+// floatTypeOf returns the type HANA stores a floating point type as, FLOAT(n) being REAL up
+// to 24 bits of precision and DOUBLE otherwise, or an empty string for other types.
+func floatTypeOf(dataType string, args []int64) string {
+	switch dataType {
+	case "REAL", "DOUBLE":
+		return dataType
+	case "FLOAT":
+		if len(args) == 1 && args[0] <= 24 {
+			return "REAL"
+		}
+		return "DOUBLE"
+	}
+	return ""
+}
+
+// GetTypeAliases returns the types stored as databaseTypeName, so that fields declared
+// with a synonym like DEC, INT or NCHAR VARYING match their columns. Text types are
+// reported as their underlying type or vice versa, and HANA Cloud only supports Unicode
+// strings and creates VARCHAR and CLOB columns as NVARCHAR and NCLOB.
+func (m Migrator) GetTypeAliases(databaseTypeName string) []string {
+	var aliases []string
+	switch strings.ToLower(databaseTypeName) {
+	case "decimal":
+		aliases = []string{"dec", "numeric"}
+	case "double":
+		aliases = []string{"float", "double precision"}
+	case "integer":
+		aliases = []string{"int"}
+	case "varchar":
+		aliases = []string{"character varying", "char varying"}
+	case "nvarchar":
+		aliases = []string{"nchar varying", "national character varying"}
+		if m.IsCloud() {
+			aliases = append(aliases, "varchar", "character varying", "char varying")
+		} else {
+			// text columns are NVARCHAR, NCLOB and BLOB columns with a full-text index
+			aliases = append(aliases, "shorttext")
 		}
-		columnTypeSQL += "FROM TABLE_COLUMNS WHERE SCHEMA_NAME = ? AND table_name = ?"
+	case "nclob":
+		if m.IsCloud() {
+			aliases = []string{"clob"}
+		} else {
+			aliases = []string{"text"}
+		}
+	case "blob":
+		aliases = []string{"bintext"}
+	case "text":
+		aliases = []string{"nclob"}
+	case "shorttext":
+		aliases = []string{"nvarchar"}
+	case "bintext":
+		aliases = []string{"blob"}
+	// ColumnTypes reports the legacy datetime types as their current names
+	case "timestamp":
+		aliases = []string{"longdate"}
+	case "date":
+		aliases = []string{"daydate"}
+	case "time":
+		aliases = []string{"secondtime"}
+	}
+	return aliases
+}
 
-		columns, err := m.DB.Raw(columnTypeSQL, currentDatabase, stmt.Table).Rows()
+// columnTypesSQL reads the columns of a table from SYS.TABLE_COLUMNS, with the key of
+// columns in the primary key or alone in a unique constraint. Like gorm expects, columns
+// of unique indexes are not reported as unique.
+const columnTypesSQL = `SELECT C.COLUMN_NAME, C.DEFAULT_VALUE, C.IS_NULLABLE, C.DATA_TYPE_NAME, C.LENGTH, C.SCALE,
+	CASE WHEN P.COLUMN_NAME IS NOT NULL THEN 'PRI' WHEN U.COLUMN_NAME IS NOT NULL THEN 'UNI' END,
+	C.GENERATION_TYPE, C.COMMENTS
+FROM SYS.TABLE_COLUMNS C
+LEFT JOIN (
+	SELECT DISTINCT COLUMN_NAME FROM SYS.CONSTRAINTS
+	WHERE SCHEMA_NAME = ? AND TABLE_NAME = ? AND IS_PRIMARY_KEY = 'TRUE'
+) P ON P.COLUMN_NAME = C.COLUMN_NAME
+LEFT JOIN (
+	SELECT DISTINCT MIN(COLUMN_NAME) AS COLUMN_NAME FROM SYS.CONSTRAINTS
+	WHERE SCHEMA_NAME = ? AND TABLE_NAME = ? AND IS_UNIQUE_KEY = 'TRUE' AND IS_PRIMARY_KEY = 'FALSE'
+	GROUP BY CONSTRAINT_NAME HAVING COUNT(*) = 1
+) U ON U.COLUMN_NAME = C.COLUMN_NAME
+WHERE C.SCHEMA_NAME = ? AND C.TABLE_NAME = ? AND C.COLUMN_NAME NOT LIKE '$%'
+ORDER BY C.POSITION`
+
+// ColumnTypes returns the columns of the table of value as stored in SYS.TABLE_COLUMNS,
+// leaving out the internal columns HANA adds for full-text indexes, which start with $.
+// Columns report whether they are part of the primary key, alone unique or an identity.
+// The table itself is not queried, which would need the privilege to select from it.
+func (m Migrator) ColumnTypes(value interface{}) (columnTypes []gorm.ColumnType, err error) {
+	columnTypes = make([]gorm.ColumnType, 0)
+	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		currentDatabase, table := m.resolveTable(stmt)
+
+		columns, err := m.DB.Raw(
+			columnTypesSQL,
+			currentDatabase, table, currentDatabase, table, currentDatabase, table,
+		).Rows()
 		if err != nil {
 			return err
 		}
 		defer columns.Close()
 
 		for columns.Next() {
-			var column migrator.ColumnType
-			var datetimePrecision sql.NullInt64
-			var extraValue sql.NullString
-			var columnKey sql.NullString
-			var values = []interface{}{&column.NameValue, &column.DefaultValueValue, &column.NullableValue, &column.DataTypeValue, &column.LengthValue, &column.ColumnTypeValue, &columnKey, &extraValue, &column.CommentValue, &column.DecimalSizeValue, &column.ScaleValue}
-
-			if !m.DisableDatetimePrecision {
-				values = append(values, &datetimePrecision)
-			}
+			var (
+				column                migrator.ColumnType
+				columnKey, extraValue sql.NullString
+				values                = []interface{}{
+					&column.NameValue, &column.DefaultValueValue, &column.NullableValue, &column.DataTypeValue,
+					&column.LengthValue, &column.ScaleValue, &columnKey, &extraValue, &column.CommentValue,
+				}
+			)
 
 			if err = columns.Scan(values...); err != nil {
 				return err
 			}
-			
-			column.PrimaryKeyValue = sql.NullBool{Bool: false, Valid: true}
-			column.UniqueValue = sql.NullBool{Bool: false, Valid: true}
-			switch columnKey.String {
-			case "PRI":
-				column.PrimaryKeyValue = sql.NullBool{Bool: true, Valid: true}
-			case "UNI":
-				column.UniqueValue = sql.NullBool{Bool: true, Valid: true}
+
+			column.PrimaryKeyValue = sql.NullBool{Bool: columnKey.String == "PRI", Valid: true}
+			column.UniqueValue = sql.NullBool{Bool: columnKey.String == "UNI", Valid: true}
+
+			// GENERATION_TYPE is BY DEFAULT AS IDENTITY or ALWAYS AS IDENTITY for identity columns
+			column.AutoIncrementValue = sql.NullBool{Bool: strings.HasSuffix(extraValue.String, "AS IDENTITY"), Valid: true}
+
+			column.DefaultValueValue.String = strings.Trim(column.DefaultValueValue.String, "'")
+
+			dataType := strings.ToUpper(column.DataTypeValue.String)
+			if legacyType, ok := legacyTypes[dataType]; ok {
+				dataType = legacyType
+				column.DataTypeValue.String = legacyType
 			}
 
-			if strings.Contains(extraValue.String, "auto_increment") {
-				column.AutoIncrementValue = sql.NullBool{Bool: true, Valid: true}
+			// LENGTH holds the precision of numeric types, which is not a column length
+			column.DecimalSizeValue = column.LengthValue
+			column.ColumnTypeValue = sql.NullString{String: dataType, Valid: true}
+			switch {
+			case hasLength(dataType):
+				column.ColumnTypeValue.String += fmt.Sprintf("(%d)", column.LengthValue.Int64)
+			case dataType == "DECIMAL" && column.ScaleValue.Valid:
+				column.ColumnTypeValue.String += fmt.Sprintf("(%d,%d)", column.LengthValue.Int64, column.ScaleValue.Int64)
+				column.LengthValue = sql.NullInt64{}
+			default:
+				column.LengthValue = sql.NullInt64{}
 			}
 
-			column.DefaultValueValue.String = strings.Trim(column.DefaultValueValue.String, "'")
-			// if m.Dialector.DontSupportNullAsDefaultValue {
-			// 	// rewrite mariadb default value like other version
-			// 	if column.DefaultValueValue.Valid && column.DefaultValueValue.String == "NULL" {
-			// 		column.DefaultValueValue.Valid = false
-			// 		column.DefaultValueValue.String = ""
-			// 	}
-			// }
-
-			if datetimePrecision.Valid {
-				column.DecimalSizeValue = datetimePrecision
+			if precision, ok := datetimePrecisions[dataType]; ok {
+				column.DecimalSizeValue = sql.NullInt64{Int64: precision, Valid: true}
+				column.ScaleValue = sql.NullInt64{}
 			}
 
-			for _, c := range rawColumnTypes {
-				if c.Name() == column.NameValue.String {
-					column.SQLColumnType = c
-					break
-				}
+			column.ScanTypeValue = scanTypeOf(dataType)
+
+			column.NameValue.String = m.fieldDBName(stmt, column.NameValue.String)
+
+			// GENERATION_TYPE is ALWAYS AS for generated columns and ALWAYS AS ROW START or
+			// END for the system time of system-versioned tables
+			if extraValue.String == "ALWAYS AS" || strings.HasPrefix(extraValue.String, "ALWAYS AS ROW ") {
+				columnTypes = append(columnTypes, generatedColumnType{tableColumnType{column}})
+			} else {
+				columnTypes = append(columnTypes, tableColumnType{column})
 			}
+		}
 
-			columnTypes = append(columnTypes, column)
+		if err := columns.Err(); err != nil {
+			return err
 		}
 
+		m.debug("column types schema=%s table=%s columns=%d", currentDatabase, table, len(columnTypes))
 		return nil
 	})
 
 	return columnTypes, err
 }
 
+// tableColumnType is a column described by the catalog alone, without the driver's column
+// type of a query, which reports no length or decimal size for types without them.
+type tableColumnType struct {
+	catalogColumnType
+}
+
+func (c tableColumnType) Length() (int64, bool) {
+	return c.LengthValue.Int64, c.LengthValue.Valid
+}
+
+func (c tableColumnType) DecimalSize() (int64, int64, bool) {
+	return c.DecimalSizeValue.Int64, c.ScaleValue.Int64, c.DecimalSizeValue.Valid
+}
+
+func (c tableColumnType) Nullable() (bool, bool) {
+	return c.NullableValue.Bool, c.NullableValue.Valid
+}
+
+// scanTypeOf returns the type go-hdb scans values of a column of dataType into.
+func scanTypeOf(dataType string) reflect.Type {
+	switch dataType {
+	case "BOOLEAN":
+		return reflect.TypeOf(false)
+	case "TINYINT":
+		return reflect.TypeOf(uint8(0))
+	case "SMALLINT":
+		return reflect.TypeOf(int16(0))
+	case "INTEGER":
+		return reflect.TypeOf(int32(0))
+	case "BIGINT":
+		return reflect.TypeOf(int64(0))
+	case "REAL":
+		return reflect.TypeOf(float32(0))
+	case "DOUBLE":
+		return reflect.TypeOf(float64(0))
+	case "DECIMAL", "SMALLDECIMAL":
+		return reflect.TypeOf(hdbdriver.Decimal{})
+	case "DATE", "TIME", "SECONDDATE", "TIMESTAMP":
+		return reflect.TypeOf(time.Time{})
+	case "NVARCHAR", "VARCHAR", "NCHAR", "CHAR", "ALPHANUM", "SHORTTEXT":
+		return reflect.TypeOf("")
+	case "VARBINARY", "BINARY":
+		return reflect.TypeOf([]byte(nil))
+	case "NCLOB", "CLOB", "BLOB", "TEXT", "BINTEXT":
+		return reflect.TypeOf(hdbdriver.Lob{})
+	}
+	return reflect.TypeOf((*interface{})(nil)).Elem()
+}
+
+// fieldDBName returns the DBName of the statement's field stored as column in the catalog,
+// as unquoted lower case field names are stored in upper case. Columns without a field
+// keep their catalog name.
+func (m Migrator) fieldDBName(stmt *gorm.Statement, column string) string {
+	if stmt.Schema != nil {
+		for _, dbName := range stmt.Schema.DBNames {
+			if m.NormalizeIdentifier(dbName) == column {
+				return dbName
+			}
+		}
+	}
+	return column
+}
+
+// legacyTypes are the types old revisions report for the datetime types.
+var legacyTypes = map[string]string{"LONGDATE": "TIMESTAMP", "DAYDATE": "DATE", "SECONDTIME": "TIME"}
+
+// datetimePrecisions are the fractional second digits of the datetime types.
+var datetimePrecisions = map[string]int64{"DATE": 0, "TIME": 0, "SECONDDATE": 0, "TIMESTAMP": 7}
+
+// hasLength reports whether LENGTH of TABLE_COLUMNS is the length of a column of dataType,
+// which is the dimension of REAL_VECTOR columns.
+func hasLength(dataType string) bool {
+	switch strings.ToUpper(dataType) {
+	case "NVARCHAR", "VARCHAR", "NCHAR", "CHAR", "VARBINARY", "BINARY", "ALPHANUM", "SHORTTEXT", "REAL_VECTOR":
+		return true
+	}
+	return false
+}
+
+// CurrentSchema splits a schema qualified table name into the schema and table name
+// stored in the catalog, defaulting to the current schema for unqualified names.
 func (m Migrator) CurrentSchema(stmt *gorm.Statement, table string) (string, string) {
 	if strings.Contains(table, ".") {
 		if tables := strings.Split(table, `.`); len(tables) == 2 {
-			return tables[0], tables[1]
+			return m.NormalizeIdentifier(strings.Trim(tables[0], `"`)), m.NormalizeIdentifier(strings.Trim(tables[1], `"`))
+		}
+	}
+
+	return m.CurrentDatabase(), m.NormalizeIdentifier(strings.Trim(table, `"`))
+}
+
+// fullTable returns the table name of the statement including the schema of schema
+// qualified TableName() results, which gorm strips from stmt.Table.
+func (m Migrator) fullTable(stmt *gorm.Statement) string {
+	if stmt.TableExpr != nil && stmt.Schema != nil && strings.Contains(stmt.Schema.Table, ".") {
+		return stmt.Schema.Table
+	}
+	return stmt.Table
+}
+
+// constraintTable returns the table expression for a constraint's table as guessed by
+// GuessConstraintAndTable, keeping the schema of the statement's own table.
+func (m Migrator) constraintTable(stmt *gorm.Statement, table string) interface{} {
+	if table == stmt.Table {
+		return m.CurrentTable(stmt)
+	}
+	return clause.Table{Name: table}
+}
+
+// indexName qualifies index names of tables in another schema with that schema, as
+// HANA creates indexes in the schema of their table.
+func (m Migrator) indexName(stmt *gorm.Statement, name string) clause.Table {
+	if table := m.fullTable(stmt); strings.Contains(table, ".") {
+		schemaName, _ := m.CurrentSchema(stmt, table)
+		return clause.Table{Name: schemaName + "." + name}
+	}
+	return clause.Table{Name: name}
+}
+
+// TableFilter selects the tables GetTablesWith returns besides the regular tables.
+type TableFilter struct {
+	Temporary bool
+	System    bool
+}
+
+// GetTables returns the names of the regular tables in the current schema, leaving out
+// temporary and system tables.
+func (m Migrator) GetTables() ([]string, error) {
+	return m.GetTablesWith(TableFilter{})
+}
+
+// GetTablesWith returns the names of the tables in the current schema, including temporary
+// and system tables as set by filter.
+func (m Migrator) GetTablesWith(filter TableFilter) (tableList []string, err error) {
+	sql := "SELECT TABLE_NAME FROM SYS.TABLES WHERE SCHEMA_NAME = ?"
+	if !filter.Temporary {
+		sql += " AND IS_TEMPORARY = 'FALSE'"
+	}
+	if !filter.System {
+		sql += " AND IS_SYSTEM_TABLE = 'FALSE'"
+	}
+	err = m.DB.Raw(sql+" ORDER BY TABLE_NAME", m.CurrentDatabase()).Scan(&tableList).Error
+	return tableList, err
+}
+
+// TableType returns the schema, name, comment and type of the table of value, which is
+// COLUMN, ROW or VIRTUAL, or TEMPORARY for temporary tables.
+func (m Migrator) TableType(value interface{}) (gorm.TableType, error) {
+	var table migrator.TableType
+	err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		schemaName, name := m.resolveTable(stmt)
+		return m.DB.Raw(
+			"SELECT SCHEMA_NAME, TABLE_NAME, CASE WHEN IS_TEMPORARY = 'TRUE' THEN 'TEMPORARY' ELSE TABLE_TYPE END, COMMENTS "+
+				"FROM SYS.TABLES WHERE SCHEMA_NAME = ? AND TABLE_NAME = ?",
+			schemaName, name,
+		).Row().Scan(&table.SchemaValue, &table.NameValue, &table.TypeValue, &table.CommentValue)
+	})
+	return table, err
+}
+
+// HasTable checks whether the table of value exists in SYS.TABLES, in the schema of a
+// schema qualified table name or else the current schema.
+func (m Migrator) HasTable(value interface{}) bool {
+	var count int64
+
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		schemaName, table := m.resolveTable(stmt)
+		return m.DB.Raw("SELECT COUNT(*) FROM SYS.TABLES WHERE SCHEMA_NAME = ? AND TABLE_NAME = ?", schemaName, table).Row().Scan(&count)
+	})
+
+	return count > 0
+}
+
+// HasColumn checks whether the column of field exists on the table of value in
+// SYS.TABLE_COLUMNS.
+func (m Migrator) HasColumn(value interface{}, field string) bool {
+	var count int64
+
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		name := field
+		if stmt.Schema != nil {
+			if f := stmt.Schema.LookUpField(field); f != nil {
+				name = f.DBName
+			}
 		}
+
+		schemaName, table := m.resolveTable(stmt)
+		return m.DB.Raw(
+			"SELECT COUNT(*) FROM SYS.TABLE_COLUMNS WHERE SCHEMA_NAME = ? AND TABLE_NAME = ? AND COLUMN_NAME = ?",
+			schemaName, table, m.NormalizeIdentifier(name),
+		).Row().Scan(&count)
+	})
+
+	return count > 0
+}
+
+// HasConstraint checks whether the constraint name exists on the table of value, or on the
+// other table of a relation for its foreign keys, in SYS.CONSTRAINTS or, for foreign keys,
+// SYS.REFERENTIAL_CONSTRAINTS.
+func (m Migrator) HasConstraint(value interface{}, name string) bool {
+	var count int64
+
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		constraint, table := m.GuessConstraintInterfaceAndTable(stmt, name)
+		if constraint != nil {
+			name = constraint.GetName()
+		}
+
+		schemaName, tableName := m.resolveTable(stmt)
+		if table != stmt.Table {
+			_, tableName = m.CurrentSchema(stmt, table)
+		}
+
+		return m.DB.Raw(
+			"SELECT COUNT(*) FROM (SELECT CONSTRAINT_NAME FROM SYS.CONSTRAINTS WHERE SCHEMA_NAME = ? AND TABLE_NAME = ? AND CONSTRAINT_NAME = ? "+
+				"UNION ALL SELECT CONSTRAINT_NAME FROM SYS.REFERENTIAL_CONSTRAINTS WHERE SCHEMA_NAME = ? AND TABLE_NAME = ? AND CONSTRAINT_NAME = ?)",
+			schemaName, tableName, m.NormalizeIdentifier(name), schemaName, tableName, m.NormalizeIdentifier(name),
+		).Row().Scan(&count)
+	})
+
+	return count > 0
+}
+
+// HasIndex checks whether the index name exists on the table of value in SYS.INDEXES or
+// SYS.FULLTEXT_INDEXES.
+func (m Migrator) HasIndex(value interface{}, name string) bool {
+	var count int64
+
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if stmt.Schema != nil {
+			if idx := stmt.Schema.LookIndex(name); idx != nil {
+				name = idx.Name
+			}
+		}
+
+		schemaName, table := m.resolveTable(stmt)
+		return m.DB.Raw(
+			"SELECT COUNT(*) FROM (SELECT INDEX_NAME FROM SYS.INDEXES WHERE SCHEMA_NAME = ? AND TABLE_NAME = ? AND INDEX_NAME = ? "+
+				"UNION ALL SELECT INDEX_NAME FROM SYS.FULLTEXT_INDEXES WHERE SCHEMA_NAME = ? AND TABLE_NAME = ? AND INDEX_NAME = ?)",
+			schemaName, table, m.NormalizeIdentifier(name), schemaName, table, m.NormalizeIdentifier(name),
+		).Row().Scan(&count)
+	})
+
+	return count > 0
+}
+
+// GetIndexes returns the indexes of the table of value from SYS.INDEXES and
+// SYS.FULLTEXT_INDEXES with their columns in order, including the index backing the primary
+// key. Unique constraints are reported as unique indexes.
+func (m Migrator) GetIndexes(value interface{}) ([]gorm.Index, error) {
+	indexes := make([]gorm.Index, 0)
+	err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		schemaName, table := m.resolveTable(stmt)
+		rows, err := m.DB.Raw(
+			"SELECT I.INDEX_NAME, I.CONSTRAINT, C.COLUMN_NAME, C.POSITION FROM SYS.INDEXES I "+
+				"JOIN SYS.INDEX_COLUMNS C ON C.SCHEMA_NAME = I.SCHEMA_NAME AND C.TABLE_NAME = I.TABLE_NAME AND C.INDEX_NAME = I.INDEX_NAME "+
+				"WHERE I.SCHEMA_NAME = ? AND I.TABLE_NAME = ? "+
+				"UNION SELECT INDEX_NAME, NULL, COLUMN_NAME, 1 FROM SYS.FULLTEXT_INDEXES WHERE SCHEMA_NAME = ? AND TABLE_NAME = ? "+
+				"ORDER BY 1, 4",
+			schemaName, table, schemaName, table,
+		).Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		var index *migrator.Index
+		for rows.Next() {
+			var (
+				name, column string
+				constraint   sql.NullString
+				position     int
+			)
+			if err := rows.Scan(&name, &constraint, &column, &position); err != nil {
+				return err
+			}
+
+			name = m.modelIndexName(stmt, name)
+			if index == nil || index.NameValue != name {
+				if index != nil {
+					indexes = append(indexes, *index)
+				}
+				index = &migrator.Index{
+					TableName:       stmt.Table,
+					NameValue:       name,
+					PrimaryKeyValue: sql.NullBool{Bool: constraint.String == "PRIMARY KEY", Valid: true},
+					UniqueValue:     sql.NullBool{Bool: constraint.Valid, Valid: true},
+				}
+			}
+			index.ColumnList = append(index.ColumnList, m.fieldDBName(stmt, column))
+		}
+		if index != nil {
+			indexes = append(indexes, *index)
+		}
+		return rows.Err()
+	})
+	return indexes, err
+}
+
+// modelIndexName returns the name of the statement's index stored as name in the catalog,
+// which holds unquoted lower case names in upper case.
+func (m Migrator) modelIndexName(stmt *gorm.Statement, name string) string {
+	if stmt.Schema != nil {
+		for _, idx := range stmt.Schema.ParseIndexes() {
+			if m.NormalizeIdentifier(idx.Name) == name {
+				return idx.Name
+			}
+		}
+	}
+	return name
+}
+
+func (m Migrator) CreateIndex(value interface{}, name string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if stmt.Schema == nil {
+			return errors.New("failed to get schema")
+		}
+		if idx := stmt.Schema.LookIndex(name); idx != nil {
+			return m.createIndex(stmt, idx)
+		}
+
+		return fmt.Errorf("failed to create index with name %s", name)
+	})
+}
+
+func (m Migrator) createIndex(stmt *gorm.Statement, idx *schema.Index) error {
+	if isFullTextIndex(idx) {
+		return m.createFullTextIndex(stmt, idx)
+	}
+
+	indexType, err := indexTypeOf(idx)
+	if err != nil {
+		return err
+	}
+
+	fields, err := m.indexExpressionColumns(stmt, idx, m.indexFields(stmt, idx))
+	if err != nil {
+		return err
+	}
+	opts := m.DB.Migrator().(migrator.BuildIndexOptionsInterface).BuildIndexOptions(fields, stmt)
+	values := []interface{}{m.indexName(stmt, idx.Name), m.CurrentTable(stmt), opts}
+
+	createIndexSQL := "CREATE "
+	if idx.Class != "" {
+		createIndexSQL += idx.Class + " "
+	}
+	if indexType != "" {
+		createIndexSQL += string(indexType) + " "
+	}
+	createIndexSQL += "INDEX ? ON ??"
+
+	if idx.Option != "" {
+		createIndexSQL += " " + idx.Option
+	}
+
+	return m.DB.Exec(createIndexSQL, values...).Error
+}
+
+// BuildIndexOptions returns the columns of an index with their ASC or DESC sort order. HANA
+// takes no prefix lengths or collations on index columns, which are left out.
+func (m Migrator) BuildIndexOptions(opts []schema.IndexOption, stmt *gorm.Statement) (results []interface{}) {
+	for _, opt := range opts {
+		str := stmt.Quote(opt.DBName)
+		if opt.Expression != "" {
+			str = opt.Expression
+		}
+
+		if sort := strings.ToUpper(strings.TrimSpace(opt.Sort)); sort == "ASC" || sort == "DESC" {
+			str += " " + sort
+		}
+		results = append(results, clause.Expr{SQL: str})
+	}
+	return
+}
+
+// indexFields returns the fields of an index ordered by their priority tag, and fields of
+// the same priority in the order of the model, which gorm's unstable sort doesn't keep.
+func (m Migrator) indexFields(stmt *gorm.Statement, idx *schema.Index) []schema.IndexOption {
+	position := make(map[*schema.Field]int, len(stmt.Schema.Fields))
+	for i, field := range stmt.Schema.Fields {
+		position[field] = i
+	}
+
+	fields := append([]schema.IndexOption(nil), idx.Fields...)
+	sort.SliceStable(fields, func(i, j int) bool {
+		pi, pj := m.indexPriority(stmt, idx.Name, fields[i].Field), m.indexPriority(stmt, idx.Name, fields[j].Field)
+		if pi != pj {
+			return pi < pj
+		}
+		return position[fields[i].Field] < position[fields[j].Field]
+	})
+	return fields
+}
+
+// indexPriority returns the priority of field in the index name from its index or
+// uniqueIndex tag, 10 like gorm without.
+func (m Migrator) indexPriority(stmt *gorm.Statement, name string, field *schema.Field) int {
+	if priority, err := strconv.Atoi(m.indexSettings(stmt, name, field)["PRIORITY"]); err == nil {
+		return priority
+	}
+	return 10
+}
+
+// indexSettings returns the settings of the index or uniqueIndex tag of field for the
+// index name, including those gorm doesn't parse into the index, or nil if it has none.
+func (m Migrator) indexSettings(stmt *gorm.Statement, name string, field *schema.Field) map[string]string {
+	for _, value := range strings.Split(field.Tag.Get("gorm"), ";") {
+		key, tag, _ := strings.Cut(value, ":")
+		if key = strings.ToUpper(strings.TrimSpace(key)); key != "INDEX" && key != "UNIQUEINDEX" {
+			continue
+		}
+
+		indexName, tagSetting, _ := strings.Cut(tag, ",")
+		settings := schema.ParseTagSetting(tagSetting, ",")
+		if indexName == "" {
+			subName := field.Name
+			if composite := settings["COMPOSITE"]; composite != "" {
+				subName = composite
+			}
+			indexName = m.DB.NamingStrategy.IndexName(stmt.Table, subName)
+		}
+		if indexName == name {
+			return settings
+		}
+	}
+	return nil
+}
+
+// DropIndex drops an index, with DROP FULLTEXT INDEX if it is a full-text index, and the
+// generated columns of the expressions of a model's index.
+func (m Migrator) DropIndex(value interface{}, name string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		var idx *schema.Index
+		if stmt.Schema != nil {
+			if idx = stmt.Schema.LookIndex(name); idx != nil {
+				name = idx.Name
+			}
+		}
+
+		if m.hasFullTextIndex(stmt, name) {
+			return m.DB.Exec("DROP FULLTEXT INDEX ?", m.indexName(stmt, name)).Error
+		}
+		if err := m.DB.Exec("DROP INDEX ?", m.indexName(stmt, name)).Error; err != nil || idx == nil {
+			return err
+		}
+		return m.dropExpressionColumns(stmt, idx)
+	})
+}
+
+// CurrentDatabase returns the schema used for catalog lookups, which is the schema of the
+// WithSchema context or the configured DefaultSchema, normalized like identifiers, or the
+// session's CURRENT_SCHEMA.
+func (m Migrator) CurrentDatabase() (name string) {
+	if schema := schemaFromContext(m.DB.Statement.Context); schema != "" {
+		return m.NormalizeIdentifier(schema)
+	}
+
+	if m.Dialector.DefaultSchema != "" {
+		return m.NormalizeIdentifier(m.Dialector.DefaultSchema)
 	}
 
-	return m.CurrentDatabase(), table
+	m.DB.Raw("SELECT CURRENT_SCHEMA FROM DUMMY").Row().Scan(&name)
+	m.debug("current schema schema=%s", name)
+	return
 }
@@ -0,0 +1,150 @@
+package hdb
+
+import (
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// checkCondition returns the condition of a check tag as HANA takes it, with identifiers
+// quoted in backticks quoted in double quotes and quoted identifiers normalized like the
+// columns they refer to. String literals are kept as they are.
+func (m Migrator) checkCondition(condition string) string {
+	var (
+		result strings.Builder
+		quote  byte
+		start  int
+	)
+	for i := 0; i < len(condition); i++ {
+		c := condition[i]
+		switch {
+		case quote == '\'':
+			result.WriteByte(c)
+			if c == '\'' {
+				quote = 0
+			}
+		case quote != 0:
+			if c == quote {
+				result.WriteString(quoteIdentifier(m.NormalizeIdentifier(condition[start:i])))
+				quote = 0
+			}
+		case c == '\'':
+			result.WriteByte(c)
+			quote = c
+		case c == '"' || c == '`':
+			quote, start = c, i+1
+		default:
+			result.WriteByte(c)
+		}
+	}
+	if quote != 0 && quote != '\'' {
+		result.WriteString(condition[start-1:])
+	}
+	return result.String()
+}
+
+// normalizeCondition returns a check condition for comparison, upper case and without
+// blanks and identifier quotes outside of string literals.
+func normalizeCondition(condition string) string {
+	var (
+		result  strings.Builder
+		literal bool
+	)
+	for _, r := range condition {
+		switch {
+		case r == '\'':
+			literal = !literal
+			result.WriteRune(r)
+		case literal:
+			result.WriteRune(r)
+		case r == '"' || unicode.IsSpace(r):
+		default:
+			result.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return result.String()
+}
+
+// checkConditions returns the conditions of the check constraints of the statement's table
+// from SYS.CONSTRAINTS by constraint name.
+func (m Migrator) checkConditions(stmt *gorm.Statement) (map[string]string, error) {
+	schemaName, table := m.resolveTable(stmt)
+	rows, err := m.DB.Raw(
+		"SELECT CONSTRAINT_NAME, CHECK_CONDITION FROM SYS.CONSTRAINTS WHERE SCHEMA_NAME = ? AND TABLE_NAME = ? AND CHECK_CONDITION IS NOT NULL",
+		schemaName, table,
+	).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conditions := map[string]string{}
+	for rows.Next() {
+		var name, condition string
+		if err := rows.Scan(&name, &condition); err != nil {
+			return nil, err
+		}
+		conditions[name] = condition
+	}
+	return conditions, rows.Err()
+}
+
+// addCheck adds the check constraint of a check tag, unless the table has a check with the
+// same condition under another name already.
+func (m Migrator) addCheck(stmt *gorm.Statement, chk *schema.CheckConstraint) error {
+	conditions, err := m.checkConditions(stmt)
+	if err != nil {
+		return err
+	}
+	condition := m.checkCondition(chk.Constraint)
+	for _, current := range conditions {
+		if normalizeCondition(current) == normalizeCondition(condition) {
+			return nil
+		}
+	}
+
+	return m.createCheck(stmt, chk.Name, condition)
+}
+
+// createCheck adds a check constraint with a condition of literal SQL, as DDL takes no
+// parameters.
+func (m Migrator) createCheck(stmt *gorm.Statement, name, condition string) error {
+	return m.DB.Exec(
+		"ALTER TABLE ? ADD CONSTRAINT ? CHECK (?)", m.CurrentTable(stmt), clause.Column{Name: name}, clause.Expr{SQL: condition},
+	).Error
+}
+
+// migrateChecks replaces the check constraints of an existing table whose condition differs
+// from the check tag of its model's field. Missing checks are added by gorm's AutoMigrate.
+func (m Migrator) migrateChecks(stmt *gorm.Statement) error {
+	if stmt.Schema == nil {
+		return nil
+	}
+	checks := stmt.Schema.ParseCheckConstraints()
+	if len(checks) == 0 {
+		return nil
+	}
+
+	conditions, err := m.checkConditions(stmt)
+	if err != nil {
+		return err
+	}
+	for _, chk := range checks {
+		current, ok := conditions[m.NormalizeIdentifier(chk.Name)]
+		condition := m.checkCondition(chk.Constraint)
+		if !ok || normalizeCondition(current) == normalizeCondition(condition) {
+			continue
+		}
+
+		if err := m.DB.Exec("ALTER TABLE ? DROP CONSTRAINT ?", m.CurrentTable(stmt), clause.Column{Name: chk.Name}).Error; err != nil {
+			return err
+		}
+		if err := m.createCheck(stmt, chk.Name, condition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
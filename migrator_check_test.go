@@ -0,0 +1,46 @@
+package hdb
+
+import "testing"
+
+func TestCheckCondition(t *testing.T) {
+	tests := []struct {
+		condition string
+		want      string
+	}{
+		{condition: "age > 18", want: "age > 18"},
+		{condition: "`age` > 18", want: `"AGE" > 18`},
+		{condition: `"age" > 18 AND "Name" <> ''`, want: `"AGE" > 18 AND "NAME" <> ''`},
+		{condition: "name <> 'it''s \"quoted\"'", want: "name <> 'it''s \"quoted\"'"},
+		{condition: "`unterminated", want: "`unterminated"},
+	}
+
+	m := dryRunDB(t, Config{}).Migrator().(Migrator)
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			if condition := m.checkCondition(tt.condition); condition != tt.want {
+				t.Errorf("got %s, want %s", condition, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeCondition(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{a: `"AGE" > 18`, b: "age>18", equal: true},
+		{a: `"STATUS" IN ('OPEN', 'CLOSED')`, b: `status in ('OPEN','CLOSED')`, equal: true},
+		{a: `"STATUS" IN ('OPEN')`, b: `"STATUS" IN ('open')`},
+		{a: `NAME <> 'a b'`, b: `NAME <> 'ab'`},
+		{a: `"AGE" > 18`, b: `"AGE" >= 18`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			if equal := normalizeCondition(tt.a) == normalizeCondition(tt.b); equal != tt.equal {
+				t.Errorf("got %s and %s equal %v, want %v", normalizeCondition(tt.a), normalizeCondition(tt.b), equal, tt.equal)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package hdb
+
+import (
+	"database/sql"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// TableCommenter sets the comment of a model's table, which CreateTable and AutoMigrate
+// write with COMMENT ON TABLE.
+type TableCommenter interface {
+	TableComment() string
+}
+
+// tableCommentOf returns the comment of the statement's model, if it implements TableCommenter.
+func tableCommentOf(stmt *gorm.Statement) (string, bool) {
+	if stmt.Schema == nil {
+		return "", false
+	}
+	if commenter, ok := reflect.New(stmt.Schema.ModelType).Interface().(TableCommenter); ok {
+		return commenter.TableComment(), true
+	}
+	return "", false
+}
+
+// TableComment returns the comment of the table of value, a model or table name, or an
+// empty string if it has none.
+func (m Migrator) TableComment(value interface{}) (comment string, err error) {
+	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		comment, err = m.tableComment(stmt)
+		return err
+	})
+	return comment, err
+}
+
+func (m Migrator) tableComment(stmt *gorm.Statement) (string, error) {
+	var (
+		comment           sql.NullString
+		schemaName, table = m.resolveTable(stmt)
+	)
+	err := m.DB.Raw(
+		"SELECT COMMENTS FROM SYS.TABLES WHERE SCHEMA_NAME = ? AND TABLE_NAME = ?", schemaName, table,
+	).Row().Scan(&comment)
+	return comment.String, err
+}
+
+// SetTableComment sets the comment of the table of value, removing it if comment is empty.
+func (m Migrator) SetTableComment(value interface{}, comment string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.setTableComment(stmt, comment)
+	})
+}
+
+func (m Migrator) setTableComment(stmt *gorm.Statement, comment string) error {
+	literal := "NULL"
+	if comment != "" {
+		literal = quoteString(comment)
+	}
+	return m.DB.Exec("COMMENT ON TABLE " + stmt.Quote(m.CurrentTable(stmt)) + " IS " + literal).Error
+}
+
+// createTableComment writes the comment of a newly created table.
+func (m Migrator) createTableComment(stmt *gorm.Statement) error {
+	if comment, ok := tableCommentOf(stmt); ok && comment != "" {
+		return m.setTableComment(stmt, comment)
+	}
+	return nil
+}
+
+// migrateTableComment updates the comment of an existing table that differs from its model's.
+func (m Migrator) migrateTableComment(stmt *gorm.Statement) error {
+	comment, ok := tableCommentOf(stmt)
+	if !ok {
+		return nil
+	}
+
+	current, err := m.tableComment(stmt)
+	if missingInDryRun(err) || err == nil && current == comment {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return m.setTableComment(stmt, comment)
+}
@@ -0,0 +1,18 @@
+package hdb
+
+import (
+	"gorm.io/gorm"
+)
+
+// SetSchemaFlexibility turns the schema flexibility of the column table of value on or off,
+// letting inserts and updates add columns to the table. Models with a FlexibleColumns field
+// create their tables schema-flexible.
+func (m Migrator) SetSchemaFlexibility(value interface{}, flexible bool) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		action := "DISABLE"
+		if flexible {
+			action = "ENABLE"
+		}
+		return m.DB.Exec("ALTER TABLE ? "+action+" SCHEMA FLEXIBILITY", m.CurrentTable(stmt)).Error
+	})
+}
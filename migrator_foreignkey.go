@@ -0,0 +1,190 @@
+package hdb
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// referentialAction returns the ON DELETE or ON UPDATE action of a constraint tag as HANA
+// takes it, RESTRICT for NO ACTION, which HANA knows no keyword for.
+func referentialAction(constraint *schema.Constraint, action string) (string, error) {
+	switch action = strings.ToUpper(strings.Join(strings.Fields(action), " ")); action {
+	case "CASCADE", "SET NULL", "SET DEFAULT", "RESTRICT":
+		return action, nil
+	case "NO ACTION":
+		return "RESTRICT", nil
+	}
+	return "", fmt.Errorf("unsupported referential action %s of constraint %s", action, constraint.Name)
+}
+
+// foreignKeyRules are the referential actions and checking options of a foreign key, as in
+// SYS.REFERENTIAL_CONSTRAINTS.
+type foreignKeyRules struct {
+	OnDelete          string
+	OnUpdate          string
+	InitiallyDeferred bool
+	NotEnforced       bool
+	NotValidated      bool
+}
+
+// foreignKeyRulesOf returns the rules of a constraint tag, RESTRICT for actions it leaves out.
+func foreignKeyRulesOf(constraint *schema.Constraint) (rules foreignKeyRules, err error) {
+	rules.OnDelete, rules.OnUpdate = "RESTRICT", "RESTRICT"
+	if constraint.OnDelete != "" {
+		if rules.OnDelete, err = referentialAction(constraint, constraint.OnDelete); err != nil {
+			return rules, err
+		}
+	}
+	if constraint.OnUpdate != "" {
+		if rules.OnUpdate, err = referentialAction(constraint, constraint.OnUpdate); err != nil {
+			return rules, err
+		}
+	}
+
+	settings := schema.ParseTagSetting(constraint.Field.TagSettings["CONSTRAINT"], ",")
+	_, rules.InitiallyDeferred = settings["INITIALLYDEFERRED"]
+	_, rules.NotEnforced = settings["NOTENFORCED"]
+	_, rules.NotValidated = settings["NOTVALIDATED"]
+	return rules, nil
+}
+
+// foreignKeyChecks returns the constraint checking clauses of a foreign key's rules, from the
+// InitiallyDeferred, NotEnforced and NotValidated settings of its constraint tag:
+//
+//	type Order struct {
+//		CustomerID uint
+//		Customer   Customer `gorm:"constraint:OnDelete:CASCADE,InitiallyDeferred"`
+//	}
+func foreignKeyChecks(rules foreignKeyRules) string {
+	var checks []string
+	if rules.InitiallyDeferred {
+		checks = append(checks, "INITIALLY DEFERRED")
+	}
+	if rules.NotEnforced {
+		checks = append(checks, "NOT ENFORCED")
+	}
+	if rules.NotValidated {
+		checks = append(checks, "NOT VALIDATED")
+	}
+	return strings.Join(checks, " ")
+}
+
+// buildForeignKey returns the definition of a foreign key like gorm's Constraint.Build,
+// with its referential actions and checking clauses as HANA takes them.
+func buildForeignKey(constraint *schema.Constraint) (string, []interface{}, error) {
+	rules, err := foreignKeyRulesOf(constraint)
+	if err != nil {
+		return "", nil, err
+	}
+
+	columns := *constraint
+	columns.OnDelete, columns.OnUpdate = "", ""
+	sql, vars := columns.Build()
+
+	if constraint.OnDelete != "" {
+		sql += " ON DELETE " + rules.OnDelete
+	}
+	if constraint.OnUpdate != "" {
+		sql += " ON UPDATE " + rules.OnUpdate
+	}
+	if checks := foreignKeyChecks(rules); checks != "" {
+		sql += " " + checks
+	}
+	return sql, vars, nil
+}
+
+// foreignKeysOf returns the foreign keys of the statement's model defined on its table.
+func (m Migrator) foreignKeysOf(stmt *gorm.Statement) []*schema.Constraint {
+	if m.DB.DisableForeignKeyConstraintWhenMigrating || m.DB.IgnoreRelationshipsWhenMigrating {
+		return nil
+	}
+
+	var constraints []*schema.Constraint
+	for _, rel := range stmt.Schema.Relationships.Relations {
+		if rel.Field.IgnoreMigration {
+			continue
+		}
+		if constraint := rel.ParseConstraint(); constraint != nil && constraint.Schema == stmt.Schema {
+			constraints = append(constraints, constraint)
+		}
+	}
+	return constraints
+}
+
+// migrateForeignKeys replaces the foreign keys of an existing table whose referential
+// actions or checking options in SYS.REFERENTIAL_CONSTRAINTS differ from the constraint tag
+// of its model's relation. Missing foreign keys are added by gorm's AutoMigrate.
+func (m Migrator) migrateForeignKeys(stmt *gorm.Statement) error {
+	if stmt.Schema == nil {
+		return nil
+	}
+	constraints := m.foreignKeysOf(stmt)
+	if len(constraints) == 0 {
+		return nil
+	}
+
+	rules, err := m.referentialRules(stmt)
+	if err != nil {
+		return err
+	}
+	for _, constraint := range constraints {
+		current, ok := rules[m.NormalizeIdentifier(constraint.Name)]
+		if !ok {
+			continue
+		}
+
+		want, err := foreignKeyRulesOf(constraint)
+		if err != nil {
+			return err
+		}
+		if want == current {
+			continue
+		}
+
+		sql, vars, err := buildForeignKey(constraint)
+		if err != nil {
+			return err
+		}
+		if err := m.DB.Exec("ALTER TABLE ? DROP CONSTRAINT ?", m.CurrentTable(stmt), clause.Column{Name: constraint.Name}).Error; err != nil {
+			return err
+		}
+		if err := m.DB.Exec("ALTER TABLE ? ADD "+sql, append([]interface{}{m.CurrentTable(stmt)}, vars...)...).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// referentialRules returns the rules of the foreign keys of the statement's table from
+// SYS.REFERENTIAL_CONSTRAINTS by constraint name.
+func (m Migrator) referentialRules(stmt *gorm.Statement) (map[string]foreignKeyRules, error) {
+	schemaName, table := m.resolveTable(stmt)
+	rows, err := m.DB.Raw(
+		"SELECT DISTINCT CONSTRAINT_NAME, DELETE_RULE, UPDATE_RULE, CHECK_TIME, IS_ENFORCED, IS_VALIDATED FROM SYS.REFERENTIAL_CONSTRAINTS WHERE SCHEMA_NAME = ? AND TABLE_NAME = ?",
+		schemaName, table,
+	).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := map[string]foreignKeyRules{}
+	for rows.Next() {
+		var name, onDelete, onUpdate, checkTime, enforced, validated string
+		if err := rows.Scan(&name, &onDelete, &onUpdate, &checkTime, &enforced, &validated); err != nil {
+			return nil, err
+		}
+		rules[name] = foreignKeyRules{
+			OnDelete:          onDelete,
+			OnUpdate:          onUpdate,
+			InitiallyDeferred: strings.EqualFold(checkTime, "INITIALLY DEFERRED"),
+			NotEnforced:       strings.EqualFold(enforced, "FALSE"),
+			NotValidated:      strings.EqualFold(validated, "FALSE"),
+		}
+	}
+	return rules, rows.Err()
+}
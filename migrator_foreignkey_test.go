@@ -0,0 +1,99 @@
+package hdb
+
+import (
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+type Buyer struct {
+	ID uint
+}
+
+type Restricted struct {
+	ID      uint
+	BuyerID uint
+	Buyer   Buyer
+}
+
+type Cascaded struct {
+	ID      uint
+	BuyerID uint
+	Buyer   Buyer `gorm:"constraint:OnDelete:CASCADE,OnUpdate:no action"`
+}
+
+type Deferred struct {
+	ID      uint
+	BuyerID uint
+	Buyer   Buyer `gorm:"constraint:OnDelete:SET NULL,InitiallyDeferred,NotEnforced,NotValidated"`
+}
+
+type Dropped struct {
+	ID      uint
+	BuyerID uint
+	Buyer   Buyer `gorm:"constraint:OnDelete:DROP"`
+}
+
+func TestBuildForeignKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		model interface{}
+		sql   string
+		rules foreignKeyRules
+		err   bool
+	}{
+		{
+			name:  "no actions",
+			model: &Restricted{},
+			sql:   `CONSTRAINT "FK_RESTRICTEDS_BUYER" FOREIGN KEY ("BUYER_ID") REFERENCES "BUYERS"("ID")`,
+			rules: foreignKeyRules{OnDelete: "RESTRICT", OnUpdate: "RESTRICT"},
+		},
+		{
+			name:  "actions",
+			model: &Cascaded{},
+			sql:   `CONSTRAINT "FK_CASCADEDS_BUYER" FOREIGN KEY ("BUYER_ID") REFERENCES "BUYERS"("ID") ON DELETE CASCADE ON UPDATE RESTRICT`,
+			rules: foreignKeyRules{OnDelete: "CASCADE", OnUpdate: "RESTRICT"},
+		},
+		{
+			name:  "checks",
+			model: &Deferred{},
+			sql:   `CONSTRAINT "FK_DEFERREDS_BUYER" FOREIGN KEY ("BUYER_ID") REFERENCES "BUYERS"("ID") ON DELETE SET NULL INITIALLY DEFERRED NOT ENFORCED NOT VALIDATED`,
+			rules: foreignKeyRules{OnDelete: "SET NULL", OnUpdate: "RESTRICT", InitiallyDeferred: true, NotEnforced: true, NotValidated: true},
+		},
+		{name: "unsupported action", model: &Dropped{}, err: true},
+	}
+
+	db := dryRunDB(t, Config{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := schema.Parse(tt.model, &sync.Map{}, db.NamingStrategy)
+			if err != nil {
+				t.Fatalf("failed to parse %T: %v", tt.model, err)
+			}
+			constraint := s.Relationships.Relations["Buyer"].ParseConstraint()
+			sql, vars, err := buildForeignKey(constraint)
+			if tt.err {
+				if err == nil {
+					t.Errorf("got %s, want an error", sql)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to build foreign key: %v", err)
+			}
+
+			stmt := &gorm.Statement{DB: db}
+			clause.Expr{SQL: sql, Vars: vars}.Build(stmt)
+			if stmt.SQL.String() != tt.sql {
+				t.Errorf("got %s, want %s", stmt.SQL.String(), tt.sql)
+			}
+
+			if rules, _ := foreignKeyRulesOf(constraint); rules != tt.rules {
+				t.Errorf("got rules %+v, want %+v", rules, tt.rules)
+			}
+		})
+	}
+}
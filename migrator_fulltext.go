@@ -0,0 +1,89 @@
+package hdb
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// fullTextClass is the class of index tags creating a full-text index on a column, which
+// take the fuzzySearchIndex, textAnalysis, languageColumn and async settings:
+//
+//	type Document struct {
+//		Language string `gorm:"size:2"`
+//		Body     string `gorm:"type:nclob;index:idx_document_body,class:FULLTEXT,fuzzySearchIndex,languageColumn:LANGUAGE,async"`
+//	}
+const fullTextClass = "FULLTEXT"
+
+// fullTextSwitches are the ON or OFF parameters of full-text indexes by their setting.
+var fullTextSwitches = []struct{ setting, parameter string }{
+	{"FUZZYSEARCHINDEX", "FUZZY SEARCH INDEX"},
+	{"TEXTANALYSIS", "TEXT ANALYSIS"},
+}
+
+// isFullTextIndex checks whether an index of a model is a full-text index.
+func isFullTextIndex(idx *schema.Index) bool {
+	return strings.EqualFold(idx.Class, fullTextClass)
+}
+
+// onOff returns the ON or OFF of a setting, which is ON when given without a value.
+func onOff(value string) string {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "OFF", "FALSE":
+		return "OFF"
+	}
+	return "ON"
+}
+
+// createFullTextIndex creates the full-text index of a model on its single column with
+// the parameters of its tag settings.
+func (m Migrator) createFullTextIndex(stmt *gorm.Statement, idx *schema.Index) error {
+	if len(idx.Fields) != 1 {
+		return fmt.Errorf("full-text index %s must be on a single column", idx.Name)
+	}
+
+	field := idx.Fields[0].Field
+	settings := m.indexSettings(stmt, idx.Name, field)
+
+	createIndexSQL := "CREATE FULLTEXT INDEX ? ON ? (?)"
+	values := []interface{}{m.indexName(stmt, idx.Name), m.CurrentTable(stmt), clause.Column{Name: field.DBName}}
+
+	if column := settings["LANGUAGECOLUMN"]; column != "" {
+		if languageField := stmt.Schema.LookUpField(column); languageField != nil {
+			column = languageField.DBName
+		}
+		createIndexSQL += " LANGUAGE COLUMN ?"
+		values = append(values, clause.Column{Name: column})
+	}
+	for _, parameter := range fullTextSwitches {
+		if value, ok := settings[parameter.setting]; ok {
+			createIndexSQL += " " + parameter.parameter + " " + onOff(value)
+		}
+	}
+	if value, ok := settings["ASYNC"]; ok {
+		if onOff(value) == "ON" {
+			createIndexSQL += " ASYNC"
+		} else {
+			createIndexSQL += " SYNC"
+		}
+	}
+
+	return m.DB.Exec(createIndexSQL, values...).Error
+}
+
+// hasFullTextIndex checks whether the index name of the statement's table is a full-text
+// index in SYS.FULLTEXT_INDEXES.
+func (m Migrator) hasFullTextIndex(stmt *gorm.Statement, name string) bool {
+	var (
+		count             int64
+		schemaName, table = m.resolveTable(stmt)
+	)
+	m.DB.Raw(
+		"SELECT COUNT(*) FROM SYS.FULLTEXT_INDEXES WHERE SCHEMA_NAME = ? AND TABLE_NAME = ? AND INDEX_NAME = ?",
+		schemaName, table, m.NormalizeIdentifier(name),
+	).Row().Scan(&count)
+	return count > 0
+}
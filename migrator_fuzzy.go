@@ -0,0 +1,74 @@
+package hdb
+
+import (
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// hasFuzzySearchIndex checks whether a field is tagged with fuzzySearchIndex, for HANA to
+// keep a fuzzy search index on its column, which speeds up CONTAINS fuzzy searches on names
+// and addresses at the cost of memory:
+//
+//	type Customer struct {
+//		Name string `gorm:"size:100;fuzzySearchIndex"`
+//	}
+func hasFuzzySearchIndex(field *schema.Field) bool {
+	value, ok := field.TagSettings["FUZZYSEARCHINDEX"]
+	return ok && onOff(value) == "ON"
+}
+
+// migrateFuzzySearchIndexes turns the fuzzy search indexes of the columns of an existing
+// table on or off that differ from the fuzzySearchIndex tags of its model's fields.
+func (m Migrator) migrateFuzzySearchIndexes(stmt *gorm.Statement) error {
+	if stmt.Schema == nil {
+		return nil
+	}
+
+	current, err := m.columnFuzzySearchIndexes(stmt)
+	if err != nil {
+		return err
+	}
+	for _, dbName := range stmt.Schema.DBNames {
+		field := stmt.Schema.FieldsByDBName[dbName]
+		indexed, ok := current[dbName]
+		if !ok || field.IgnoreMigration || isGeneratedField(field) || indexed == hasFuzzySearchIndex(field) {
+			continue
+		}
+
+		// the column definition states FUZZY SEARCH INDEX ON for tagged fields
+		definition := m.alterDataTypeOf(field)
+		if indexed {
+			definition.SQL += " FUZZY SEARCH INDEX OFF"
+		}
+		if err := m.DB.Exec("ALTER TABLE ? ALTER (? ?)", m.CurrentTable(stmt), clause.Column{Name: dbName}, definition).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnFuzzySearchIndexes returns whether the columns of the statement's table have a
+// fuzzy search index by the DBName of their fields.
+func (m Migrator) columnFuzzySearchIndexes(stmt *gorm.Statement) (map[string]bool, error) {
+	schemaName, table := m.resolveTable(stmt)
+	rows, err := m.DB.Raw(
+		"SELECT COLUMN_NAME, FUZZY_SEARCH_INDEX FROM SYS.TABLE_COLUMNS WHERE SCHEMA_NAME = ? AND TABLE_NAME = ?", schemaName, table,
+	).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	indexes := map[string]bool{}
+	for rows.Next() {
+		var column, indexed sql.NullString
+		if err := rows.Scan(&column, &indexed); err != nil {
+			return nil, err
+		}
+		indexes[m.fieldDBName(stmt, column.String)] = indexed.String == "TRUE"
+	}
+	return indexes, rows.Err()
+}
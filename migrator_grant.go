@@ -0,0 +1,106 @@
+package hdb
+
+import (
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Grant is a set of privileges granted to a user or role, like the application's runtime
+// user, on the objects the migrator creates.
+type Grant struct {
+	Grantee string
+	// Privileges are the privileges on tables and views, by default SELECT, INSERT, UPDATE
+	// and DELETE on tables and SELECT on views and virtual tables. Procedures and functions
+	// are granted EXECUTE.
+	Privileges []string
+}
+
+// Granter sets the grants on a model's table or view, which AutoMigrate grants instead of
+// Config.Grants.
+type Granter interface {
+	Grants() []Grant
+}
+
+// tablePrivileges and viewPrivileges are the privileges granted on tables and on views and
+// virtual tables by default.
+var (
+	tablePrivileges = []string{"SELECT", "INSERT", "UPDATE", "DELETE"}
+	viewPrivileges  = []string{"SELECT"}
+)
+
+// grantsOf returns the grants on the statement's model, those of Config.Grants unless it
+// implements Granter.
+func (m Migrator) grantsOf(stmt *gorm.Statement) []Grant {
+	if stmt.Schema != nil {
+		if granter, ok := reflect.New(stmt.Schema.ModelType).Interface().(Granter); ok {
+			return granter.Grants()
+		}
+	}
+	return m.Grants
+}
+
+// GrantPrivileges grants the privileges of grant on the table or view of value, the
+// default privileges of a table without.
+func (m Migrator) GrantPrivileges(value interface{}, grant Grant) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.grantPrivileges(stmt, grant, tablePrivileges)
+	})
+}
+
+func (m Migrator) grantPrivileges(stmt *gorm.Statement, grant Grant, defaults []string) error {
+	privileges := grant.Privileges
+	if len(privileges) == 0 {
+		privileges = defaults
+	}
+	return m.DB.Exec(
+		"GRANT "+strings.ToUpper(strings.Join(privileges, ", "))+" ON ? TO ?", m.CurrentTable(stmt), clause.Table{Name: grant.Grantee},
+	).Error
+}
+
+// RevokePrivileges revokes the privileges of grant on the table or view of value, the
+// default privileges of a table without.
+func (m Migrator) RevokePrivileges(value interface{}, grant Grant) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		privileges := grant.Privileges
+		if len(privileges) == 0 {
+			privileges = tablePrivileges
+		}
+		return m.DB.Exec(
+			"REVOKE "+strings.ToUpper(strings.Join(privileges, ", "))+" ON ? FROM ?", m.CurrentTable(stmt), clause.Table{Name: grant.Grantee},
+		).Error
+	})
+}
+
+// GrantExecute grants EXECUTE on the procedure or function name to the grantees.
+func (m Migrator) GrantExecute(name string, grantees ...string) error {
+	for _, grantee := range grantees {
+		if err := m.DB.Exec("GRANT EXECUTE ON ? TO ?", clause.Table{Name: name}, clause.Table{Name: grantee}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateGrants grants the grants of the statement's model on its table or view, which
+// HANA takes again without complaint.
+func (m Migrator) migrateGrants(stmt *gorm.Statement, defaults []string) error {
+	for _, grant := range m.grantsOf(stmt) {
+		if err := m.grantPrivileges(stmt, grant, defaults); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// grantExecute grants EXECUTE on the procedure or function name to the grantees of
+// Config.Grants.
+func (m Migrator) grantExecute(name string) error {
+	grantees := make([]string, 0, len(m.Grants))
+	for _, grant := range m.Grants {
+		grantees = append(grantees, grant.Grantee)
+	}
+	return m.GrantExecute(name, grantees...)
+}
@@ -0,0 +1,128 @@
+package hdb
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// IndexType is the structure of an index, set with the type tag of an index:
+//
+//	type Order struct {
+//		CustomerID uint   `gorm:"index:,type:inverted hash"`
+//		Status     string `gorm:"index:,type:cpbtree"`
+//	}
+//
+// Column tables take INVERTED VALUE, INVERTED HASH, which saves memory on composite keys, and
+// INVERTED INDIVIDUAL for unique composite indexes, row tables BTREE and CPBTREE.
+type IndexType string
+
+const (
+	BTreeIndex              IndexType = "BTREE"
+	CPBTreeIndex            IndexType = "CPBTREE"
+	InvertedValueIndex      IndexType = "INVERTED VALUE"
+	InvertedHashIndex       IndexType = "INVERTED HASH"
+	InvertedIndividualIndex IndexType = "INVERTED INDIVIDUAL"
+)
+
+// indexTypeOf returns the type of an index from its type tag, blank for HANA's default.
+func indexTypeOf(idx *schema.Index) (IndexType, error) {
+	if idx.Type == "" {
+		return "", nil
+	}
+	switch indexType := IndexType(strings.ToUpper(strings.Join(strings.Fields(idx.Type), " "))); indexType {
+	case BTreeIndex, CPBTreeIndex, InvertedValueIndex, InvertedHashIndex, InvertedIndividualIndex:
+		return indexType, nil
+	}
+	return "", fmt.Errorf("unsupported type %s of index %s", idx.Type, idx.Name)
+}
+
+// GetIndexType returns the type of the index name on the table of value from SYS.INDEXES,
+// without the UNIQUE HANA adds for unique indexes.
+func (m Migrator) GetIndexType(value interface{}, name string) (indexType IndexType, err error) {
+	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if stmt.Schema != nil {
+			if idx := stmt.Schema.LookIndex(name); idx != nil {
+				name = idx.Name
+			}
+		}
+
+		var (
+			catalogType       sql.NullString
+			schemaName, table = m.resolveTable(stmt)
+		)
+		err := m.DB.Raw(
+			"SELECT INDEX_TYPE FROM SYS.INDEXES WHERE SCHEMA_NAME = ? AND TABLE_NAME = ? AND INDEX_NAME = ?",
+			schemaName, table, m.NormalizeIdentifier(name),
+		).Row().Scan(&catalogType)
+		indexType = IndexType(strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(catalogType.String, "UNIQUE"), "_")))
+		return err
+	})
+	return indexType, err
+}
+
+// expressionColumn returns the name of the generated column indexing the expression of an
+// index field.
+func expressionColumn(idx *schema.Index, opt schema.IndexOption) string {
+	return idx.Name + "_" + opt.DBName
+}
+
+// indexExpressionColumns returns the fields of an index with their expressions replaced by
+// generated columns, which are added to the table if missing. HANA indexes columns only, so
+// an index tagged with expression:UPPER(EMAIL) is on a column GENERATED ALWAYS AS
+// (UPPER(EMAIL)) with the type of its field or of the index tag's expressionType.
+func (m Migrator) indexExpressionColumns(stmt *gorm.Statement, idx *schema.Index, fields []schema.IndexOption) ([]schema.IndexOption, error) {
+	for i, opt := range fields {
+		if opt.Expression == "" {
+			continue
+		}
+
+		column := expressionColumn(idx, opt)
+		if !m.HasColumn(stmt.Model, column) {
+			dataType := m.indexSettings(stmt, idx.Name, opt.Field)["EXPRESSIONTYPE"]
+			if dataType == "" {
+				dataType = m.Migrator.DataTypeOf(opt.Field)
+			}
+			if err := m.DB.Exec(
+				"ALTER TABLE ? ADD (? "+dataType+" GENERATED ALWAYS AS ("+opt.Expression+"))", m.CurrentTable(stmt), clause.Column{Name: column},
+			).Error; err != nil {
+				return nil, err
+			}
+		}
+
+		field := *opt.Field
+		field.DBName = column
+		fields[i].Field, fields[i].Expression = &field, ""
+	}
+	return fields, nil
+}
+
+// dropExpressionColumns drops the generated columns of the expressions of an index.
+func (m Migrator) dropExpressionColumns(stmt *gorm.Statement, idx *schema.Index) error {
+	for _, opt := range idx.Fields {
+		if column := expressionColumn(idx, opt); opt.Expression != "" && m.HasColumn(stmt.Model, column) {
+			if err := m.DB.Exec("ALTER TABLE ? DROP (?)", m.CurrentTable(stmt), clause.Column{Name: column}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// expressionColumns returns the generated columns of the expressions of the indexes of the
+// statement's model by their name in the catalog.
+func (m Migrator) expressionColumns(stmt *gorm.Statement) map[string]bool {
+	columns := map[string]bool{}
+	for _, idx := range stmt.Schema.ParseIndexes() {
+		for _, opt := range idx.Fields {
+			if opt.Expression != "" {
+				columns[m.NormalizeIdentifier(expressionColumn(&idx, opt))] = true
+			}
+		}
+	}
+	return columns
+}
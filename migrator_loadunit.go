@@ -0,0 +1,169 @@
+package hdb
+
+import (
+	"database/sql"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// LoadUnit is how HANA loads a table, partition or column into memory. Page loadable data
+// is kept in the Native Storage Extension (NSE) and loaded page by page as read.
+type LoadUnit string
+
+const (
+	ColumnLoadable  LoadUnit = "COLUMN"
+	PageLoadable    LoadUnit = "PAGE"
+	DefaultLoadable LoadUnit = "DEFAULT"
+)
+
+// TableLoadUniter sets the load unit of a model's table, which CreateTable and AutoMigrate
+// apply. Columns set their own with a loadUnit:page or loadUnit:column tag.
+type TableLoadUniter interface {
+	TableLoadUnit() LoadUnit
+}
+
+// tableLoadUnitOf returns the load unit of the statement's model, if it implements
+// TableLoadUniter.
+func tableLoadUnitOf(stmt *gorm.Statement) (LoadUnit, bool) {
+	if stmt.Schema == nil {
+		return "", false
+	}
+	if uniter, ok := reflect.New(stmt.Schema.ModelType).Interface().(TableLoadUniter); ok {
+		return uniter.TableLoadUnit(), true
+	}
+	return "", false
+}
+
+// loadUnitOf returns the load unit of a field tagged with loadUnit.
+func loadUnitOf(field *schema.Field) LoadUnit {
+	return LoadUnit(strings.ToUpper(field.TagSettings["LOADUNIT"]))
+}
+
+// loadable returns the load unit clause, like PAGE LOADABLE.
+func (u LoadUnit) loadable() string {
+	return string(u) + " LOADABLE"
+}
+
+// TableLoadUnit returns the load unit of the table of value from SYS.TABLES.
+func (m Migrator) TableLoadUnit(value interface{}) (unit LoadUnit, err error) {
+	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		unit, err = m.tableLoadUnit(stmt)
+		return err
+	})
+	return unit, err
+}
+
+func (m Migrator) tableLoadUnit(stmt *gorm.Statement) (LoadUnit, error) {
+	var (
+		unit              sql.NullString
+		schemaName, table = m.resolveTable(stmt)
+	)
+	err := m.DB.Raw(
+		"SELECT LOAD_UNIT FROM SYS.TABLES WHERE SCHEMA_NAME = ? AND TABLE_NAME = ?", schemaName, table,
+	).Row().Scan(&unit)
+	return LoadUnit(unit.String), err
+}
+
+// SetTableLoadUnit sets the load unit of the table of value, moving it into or out of NSE.
+// Partitions and columns with load units of their own keep them.
+func (m Migrator) SetTableLoadUnit(value interface{}, unit LoadUnit) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.DB.Exec("ALTER TABLE ? "+unit.loadable(), m.CurrentTable(stmt)).Error
+	})
+}
+
+// SetColumnLoadUnit sets the load unit of the column of field.
+func (m Migrator) SetColumnLoadUnit(value interface{}, field string, unit LoadUnit) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		name := field
+		if stmt.Schema != nil {
+			if f := stmt.Schema.LookUpField(field); f != nil {
+				name = f.DBName
+			}
+		}
+		return m.DB.Exec("ALTER TABLE ? ALTER (? ALTER "+unit.loadable()+")", m.CurrentTable(stmt), clause.Column{Name: name}).Error
+	})
+}
+
+// SetPartitionLoadUnit sets the load unit of the partition with the ID of Partitions, like
+// to move the partitions of past years into NSE.
+func (m Migrator) SetPartitionLoadUnit(value interface{}, id int, unit LoadUnit) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.DB.Exec("ALTER TABLE ? ALTER PARTITION "+strconv.Itoa(id)+" "+unit.loadable(), m.CurrentTable(stmt)).Error
+	})
+}
+
+// migrateLoadUnits sets the load units of an existing table and its columns that differ
+// from its model's.
+func (m Migrator) migrateLoadUnits(stmt *gorm.Statement) error {
+	if m.DontSupportNSE || stmt.Schema == nil {
+		return nil
+	}
+
+	if unit, ok := tableLoadUnitOf(stmt); ok && unit != "" {
+		current, err := m.tableLoadUnit(stmt)
+		if missingInDryRun(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if current != unit {
+			if err := m.DB.Exec("ALTER TABLE ? "+unit.loadable(), m.CurrentTable(stmt)).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	var fields []*schema.Field
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName != "" && !field.IgnoreMigration && loadUnitOf(field) != "" {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	current, err := m.columnLoadUnits(stmt)
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		if unit, ok := current[field.DBName]; ok && unit != loadUnitOf(field) {
+			if err := m.DB.Exec(
+				"ALTER TABLE ? ALTER (? ALTER "+loadUnitOf(field).loadable()+")", m.CurrentTable(stmt), clause.Column{Name: field.DBName},
+			).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// columnLoadUnits returns the load units of the columns of the statement's table by the
+// DBName of their fields.
+func (m Migrator) columnLoadUnits(stmt *gorm.Statement) (map[string]LoadUnit, error) {
+	schemaName, table := m.resolveTable(stmt)
+	rows, err := m.DB.Raw(
+		"SELECT COLUMN_NAME, LOAD_UNIT FROM SYS.TABLE_COLUMNS WHERE SCHEMA_NAME = ? AND TABLE_NAME = ?", schemaName, table,
+	).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	units := map[string]LoadUnit{}
+	for rows.Next() {
+		var column, unit sql.NullString
+		if err := rows.Scan(&column, &unit); err != nil {
+			return nil, err
+		}
+		units[m.fieldDBName(stmt, column.String)] = LoadUnit(unit.String)
+	}
+	return units, rows.Err()
+}
@@ -0,0 +1,53 @@
+package hdb
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+// debug logs a migrator diagnostic with the configured logger, which shows it in Info mode
+// like with db.Debug().
+func (m Migrator) debug(msg string, data ...interface{}) {
+	m.DB.Logger.Info(m.DB.Statement.Context, "hdb migrator: "+msg, data...)
+}
+
+// ddlLogger traces the DDL statements run by the migrator regardless of the log level if
+// TraceDDL is set, other statements are logged as configured.
+type ddlLogger struct {
+	logger.Interface
+	ddl logger.Interface
+}
+
+func newDDLLogger(l logger.Interface) ddlLogger {
+	return ddlLogger{Interface: l, ddl: l.LogMode(logger.Info)}
+}
+
+func (l ddlLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return ddlLogger{Interface: l.Interface.LogMode(level), ddl: l.ddl}
+}
+
+func (l ddlLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, rowsAffected := fc()
+	traced := func() (string, int64) { return sql, rowsAffected }
+	if isDDL(sql) {
+		l.ddl.Trace(ctx, begin, traced, err)
+		return
+	}
+	l.Interface.Trace(ctx, begin, traced, err)
+}
+
+func isDDL(sql string) bool {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return false
+	}
+
+	switch strings.ToUpper(fields[0]) {
+	case "CREATE", "ALTER", "DROP", "RENAME", "COMMENT", "TRUNCATE", "GRANT", "REVOKE":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package hdb
+
+import (
+	"database/sql"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// maskOf returns the mask expression of a field tagged with mask, which HANA shows users
+// without the UNMASKED privilege instead of the column's values:
+//
+//	type Customer struct {
+//		ID  uint
+//		IBAN string `gorm:"size:34;mask:LEFT(IBAN, 4) || '****'"`
+//	}
+func maskOf(field *schema.Field) string {
+	return strings.TrimSpace(field.TagSettings["MASK"])
+}
+
+// maskClause returns the WITH MASK clause of the masked fields of a model's table, or an
+// empty clause if it has none.
+func maskClause(stmt *gorm.Statement) clause.Expr {
+	var (
+		sql  []string
+		vars []interface{}
+	)
+	for _, dbName := range stmt.Schema.DBNames {
+		field := stmt.Schema.FieldsByDBName[dbName]
+		if mask := maskOf(field); mask != "" && !field.IgnoreMigration {
+			sql = append(sql, "? USING "+mask)
+			vars = append(vars, clause.Column{Name: dbName})
+		}
+	}
+	if len(sql) == 0 {
+		return clause.Expr{}
+	}
+	return clause.Expr{SQL: "WITH MASK (" + strings.Join(sql, ", ") + ")", Vars: vars}
+}
+
+// setMask adds, replaces or with an empty mask drops the mask of the column of a field.
+func (m Migrator) setMask(stmt *gorm.Statement, column, mask string, exists bool) error {
+	switch {
+	case mask == "":
+		return m.DB.Exec("ALTER TABLE ? DROP MASK (?)", m.CurrentTable(stmt), clause.Column{Name: column}).Error
+	case exists:
+		return m.DB.Exec("ALTER TABLE ? ALTER MASK (? USING "+mask+")", m.CurrentTable(stmt), clause.Column{Name: column}).Error
+	}
+	return m.DB.Exec("ALTER TABLE ? ADD MASK (? USING "+mask+")", m.CurrentTable(stmt), clause.Column{Name: column}).Error
+}
+
+// migrateMasks adds, replaces and drops the masks of the columns of an existing table that
+// differ from the mask tags of its model's fields.
+func (m Migrator) migrateMasks(stmt *gorm.Statement) error {
+	if stmt.Schema == nil {
+		return nil
+	}
+
+	current, err := m.columnMasks(stmt)
+	if err != nil {
+		return err
+	}
+	for _, dbName := range stmt.Schema.DBNames {
+		field := stmt.Schema.FieldsByDBName[dbName]
+		currentMask, ok := current[dbName]
+		if !ok || field.IgnoreMigration {
+			continue
+		}
+		// HANA keeps mask expressions as written, but for surrounding blanks
+		if mask := maskOf(field); !strings.EqualFold(mask, currentMask) {
+			if err := m.setMask(stmt, dbName, mask, currentMask != ""); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// columnMasks returns the mask expressions of the columns of the statement's table by the
+// DBName of their fields, empty for columns without.
+func (m Migrator) columnMasks(stmt *gorm.Statement) (map[string]string, error) {
+	schemaName, table := m.resolveTable(stmt)
+	rows, err := m.DB.Raw(
+		"SELECT COLUMN_NAME, MASK_EXPRESSION FROM SYS.TABLE_COLUMNS WHERE SCHEMA_NAME = ? AND TABLE_NAME = ?", schemaName, table,
+	).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	masks := map[string]string{}
+	for rows.Next() {
+		var column, mask sql.NullString
+		if err := rows.Scan(&column, &mask); err != nil {
+			return nil, err
+		}
+		masks[m.fieldDBName(stmt, column.String)] = strings.TrimSpace(mask.String)
+	}
+	return masks, rows.Err()
+}
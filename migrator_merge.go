@@ -0,0 +1,82 @@
+package hdb
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// DeltaMerge are the options of merging the delta storage of a column table into its main
+// storage.
+type DeltaMerge struct {
+	// Partition is the ID of Partitions of the partition to merge, 0 for all.
+	Partition int
+	// Smart leaves the merge to HANA's merge decision function, which skips merges it deems
+	// not worthwhile.
+	Smart bool
+	// Forced merges regardless of the system load.
+	Forced bool
+	// Memory merges in memory only, without persisting the new main storage.
+	Memory bool
+}
+
+// clauses returns the options as MERGE DELTA clauses.
+func (o DeltaMerge) clauses() string {
+	var clauses, parameters []string
+	if o.Partition > 0 {
+		clauses = append(clauses, "PART "+strconv.Itoa(o.Partition))
+	}
+	if o.Smart {
+		parameters = append(parameters, "'SMART_MERGE' = 'ON'")
+	}
+	if o.Forced {
+		parameters = append(parameters, "'FORCED_MERGE' = 'ON'")
+	}
+	if o.Memory {
+		parameters = append(parameters, "'MEMORY_MERGE' = 'ON'")
+	}
+	if len(parameters) > 0 {
+		clauses = append(clauses, "WITH PARAMETERS ("+strings.Join(parameters, ", ")+")")
+	}
+	return strings.Join(clauses, " ")
+}
+
+// MergeDelta merges the delta storage of the table of value into its main storage, like
+// after bulk loads into tables with automatic merges turned off:
+//
+//	m.MergeDelta(&Event{}, hdb.DeltaMerge{Smart: true})
+//	// MERGE DELTA OF "EVENTS" WITH PARAMETERS ('SMART_MERGE' = 'ON')
+func (m Migrator) MergeDelta(value interface{}, options DeltaMerge) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.DB.Exec(strings.TrimSpace("MERGE DELTA OF ? "+options.clauses()), m.CurrentTable(stmt)).Error
+	})
+}
+
+// DeltaSize is the size of the delta storage of a column table.
+type DeltaSize struct {
+	// Records is the number of records in the delta storage, including those deleted or
+	// updated since.
+	Records int64
+	// MemorySize is the memory size of the delta storage in bytes.
+	MemorySize int64
+}
+
+// DeltaSize returns the size of the delta storage of the loaded partitions of the table of
+// value from M_CS_TABLES.
+func (m Migrator) DeltaSize(value interface{}) (size DeltaSize, err error) {
+	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		var (
+			records, memorySize sql.NullInt64
+			schemaName, table   = m.resolveTable(stmt)
+		)
+		err := m.DB.Raw(
+			"SELECT SUM(RAW_RECORD_COUNT_IN_DELTA), SUM(MEMORY_SIZE_IN_DELTA) FROM SYS.M_CS_TABLES WHERE SCHEMA_NAME = ? AND TABLE_NAME = ?",
+			schemaName, table,
+		).Row().Scan(&records, &memorySize)
+		size = DeltaSize{Records: records.Int64, MemorySize: memorySize.Int64}
+		return err
+	})
+	return size, err
+}
@@ -0,0 +1,140 @@
+package hdb
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// TableOptions are the delta merge and unload options of a column table.
+type TableOptions struct {
+	// NoAutoMerge turns off the automatic delta merge of the table, which high-ingest tables
+	// then merge by hand at times of their choosing.
+	NoAutoMerge bool
+	// UnloadPriority is the priority from 0, never, to 9, first, of unloading the table from
+	// memory when it runs short. Nil keeps the default of HANA.
+	UnloadPriority *int
+}
+
+// TableOptioner sets the options of a model's table, which CreateTable declares and
+// AutoMigrate applies to existing tables.
+type TableOptioner interface {
+	TableOptions() TableOptions
+}
+
+// tableOptionsOf returns the options of the statement's model, if it implements
+// TableOptioner.
+func tableOptionsOf(stmt *gorm.Statement) (TableOptions, bool) {
+	if stmt.Schema == nil {
+		return TableOptions{}, false
+	}
+	if optioner, ok := reflect.New(stmt.Schema.ModelType).Interface().(TableOptioner); ok {
+		return optioner.TableOptions(), true
+	}
+	return TableOptions{}, false
+}
+
+// clauses returns the options as CREATE TABLE clauses.
+func (o TableOptions) clauses() string {
+	clauses := "AUTO MERGE"
+	if o.NoAutoMerge {
+		clauses = "NO AUTO MERGE"
+	}
+	if o.UnloadPriority != nil {
+		clauses += " UNLOAD PRIORITY " + strconv.Itoa(*o.UnloadPriority)
+	}
+	return clauses
+}
+
+// validateUnloadPriority rejects unload priorities HANA doesn't take.
+func validateUnloadPriority(priority int) error {
+	if priority < 0 || priority > 9 {
+		return fmt.Errorf("unload priority %d is not between 0 and 9", priority)
+	}
+	return nil
+}
+
+// SetAutoMerge turns the automatic delta merge of the table of value on or off.
+func (m Migrator) SetAutoMerge(value interface{}, autoMerge bool) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.setAutoMerge(stmt, autoMerge)
+	})
+}
+
+func (m Migrator) setAutoMerge(stmt *gorm.Statement, autoMerge bool) error {
+	action := "DISABLE"
+	if autoMerge {
+		action = "ENABLE"
+	}
+	return m.DB.Exec("ALTER TABLE ? "+action+" AUTOMERGE", m.CurrentTable(stmt)).Error
+}
+
+// SetUnloadPriority sets the priority from 0 to 9 of unloading the table of value.
+func (m Migrator) SetUnloadPriority(value interface{}, priority int) error {
+	if err := validateUnloadPriority(priority); err != nil {
+		return err
+	}
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.setUnloadPriority(stmt, priority)
+	})
+}
+
+func (m Migrator) setUnloadPriority(stmt *gorm.Statement, priority int) error {
+	return m.DB.Exec("ALTER TABLE ? UNLOAD PRIORITY "+strconv.Itoa(priority), m.CurrentTable(stmt)).Error
+}
+
+// GetTableOptions returns the options of the table of value from SYS.TABLES.
+func (m Migrator) GetTableOptions(value interface{}) (options TableOptions, err error) {
+	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		options, err = m.tableOptions(stmt)
+		return err
+	})
+	return options, err
+}
+
+func (m Migrator) tableOptions(stmt *gorm.Statement) (TableOptions, error) {
+	var (
+		autoMerge         string
+		priority          sql.NullInt64
+		schemaName, table = m.resolveTable(stmt)
+	)
+	err := m.DB.Raw(
+		"SELECT AUTO_MERGE_ON, UNLOAD_PRIORITY FROM SYS.TABLES WHERE SCHEMA_NAME = ? AND TABLE_NAME = ?", schemaName, table,
+	).Row().Scan(&autoMerge, &priority)
+
+	options := TableOptions{NoAutoMerge: autoMerge == "FALSE"}
+	if priority.Valid {
+		unloadPriority := int(priority.Int64)
+		options.UnloadPriority = &unloadPriority
+	}
+	return options, err
+}
+
+// migrateTableOptions applies the options of the statement's model that differ from its
+// existing table's.
+func (m Migrator) migrateTableOptions(stmt *gorm.Statement) error {
+	options, ok := tableOptionsOf(stmt)
+	if !ok {
+		return nil
+	}
+
+	current, err := m.tableOptions(stmt)
+	if missingInDryRun(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if current.NoAutoMerge != options.NoAutoMerge {
+		if err := m.setAutoMerge(stmt, !options.NoAutoMerge); err != nil {
+			return err
+		}
+	}
+	if options.UnloadPriority != nil && (current.UnloadPriority == nil || *current.UnloadPriority != *options.UnloadPriority) {
+		return m.setUnloadPriority(stmt, *options.UnloadPriority)
+	}
+	return nil
+}
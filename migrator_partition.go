@@ -0,0 +1,255 @@
+package hdb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PartitionType is the way a partitioned table spreads its rows over its partitions.
+type PartitionType string
+
+const (
+	HashPartitioning       PartitionType = "HASH"
+	RangePartitioning      PartitionType = "RANGE"
+	RoundRobinPartitioning PartitionType = "ROUNDROBIN"
+)
+
+// Partitioning is the partitioning of a table. Columns are field or column names and
+// Partitions the number of HASH or ROUNDROBIN partitions, or one per host if zero. RANGE
+// partitions are given by Ranges, with a partition for the remaining rows if Others is set.
+type Partitioning struct {
+	Type       PartitionType
+	Columns    []string
+	Partitions int
+	Ranges     []PartitionRange
+	Others     bool
+}
+
+// PartitionRange is a RANGE partition holding the rows from Min up to but excluding Max, or
+// the rows equal to Value if set. Bounds are written as string literals, which HANA converts
+// to the type of the partitioning column, like "2024-01-01" or "100".
+type PartitionRange struct {
+	Min   string
+	Max   string
+	Value string
+}
+
+// TablePartitioner sets the partitioning of a model's table, which CreateTable writes with
+// PARTITION BY.
+type TablePartitioner interface {
+	TablePartitioning() Partitioning
+}
+
+// tablePartitioningOf returns the partitioning of the statement's model, if it implements
+// TablePartitioner.
+func tablePartitioningOf(stmt *gorm.Statement) (Partitioning, bool) {
+	if stmt.Schema == nil {
+		return Partitioning{}, false
+	}
+	if partitioner, ok := reflect.New(stmt.Schema.ModelType).Interface().(TablePartitioner); ok {
+		return partitioner.TablePartitioning(), true
+	}
+	return Partitioning{}, false
+}
+
+// partitionClause returns the PARTITION BY clause of a partitioning and its column vars.
+func (m Migrator) partitionClause(stmt *gorm.Statement, partitioning Partitioning) (string, []interface{}, error) {
+	var (
+		clauseSQL = "PARTITION BY " + string(partitioning.Type)
+		vars      []interface{}
+	)
+
+	switch partitioning.Type {
+	case HashPartitioning, RangePartitioning:
+		if len(partitioning.Columns) == 0 {
+			return "", nil, fmt.Errorf("%s partitioning of %s has no columns", partitioning.Type, stmt.Table)
+		}
+		columns := make([]interface{}, 0, len(partitioning.Columns))
+		for _, name := range partitioning.Columns {
+			if stmt.Schema != nil {
+				if field := stmt.Schema.LookUpField(name); field != nil {
+					name = field.DBName
+				}
+			}
+			columns = append(columns, clause.Column{Name: name})
+		}
+		clauseSQL += " ?"
+		vars = append(vars, columns)
+	case RoundRobinPartitioning:
+	default:
+		return "", nil, fmt.Errorf("unsupported partitioning of %s: %q", stmt.Table, partitioning.Type)
+	}
+
+	if partitioning.Type != RangePartitioning {
+		if partitioning.Partitions > 0 {
+			clauseSQL += " PARTITIONS " + strconv.Itoa(partitioning.Partitions)
+		} else {
+			clauseSQL += " PARTITIONS GET_NUM_SERVERS()"
+		}
+		return clauseSQL, vars, nil
+	}
+
+	if len(partitioning.Ranges) == 0 && !partitioning.Others {
+		return "", nil, fmt.Errorf("RANGE partitioning of %s has no partitions", stmt.Table)
+	}
+	partitions := make([]string, 0, len(partitioning.Ranges)+1)
+	for _, partitionRange := range partitioning.Ranges {
+		partitions = append(partitions, partitionRange.spec())
+	}
+	if partitioning.Others {
+		partitions = append(partitions, "PARTITION OTHERS")
+	}
+	return clauseSQL + " (" + strings.Join(partitions, ", ") + ")", vars, nil
+}
+
+// spec returns the partition specification of a RANGE partition.
+func (r PartitionRange) spec() string {
+	if r.Value != "" {
+		return "PARTITION VALUE = " + quoteString(r.Value)
+	}
+	return "PARTITION " + quoteString(r.Min) + " <= VALUES < " + quoteString(r.Max)
+}
+
+// PartitionTable partitions the table of value, or repartitions it if it is partitioned
+// already, moving its rows into the new partitions:
+//
+//	m.PartitionTable(&Log{}, hdb.Partitioning{Type: hdb.HashPartitioning, Columns: []string{"ID"}, Partitions: 4})
+//	// ALTER TABLE "LOGS" PARTITION BY HASH ("ID") PARTITIONS 4
+func (m Migrator) PartitionTable(value interface{}, partitioning Partitioning) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		clauseSQL, vars, err := m.partitionClause(stmt, partitioning)
+		if err != nil {
+			return err
+		}
+		return m.DB.Exec("ALTER TABLE ? "+clauseSQL, append([]interface{}{m.CurrentTable(stmt)}, vars...)...).Error
+	})
+}
+
+// TablePartitioning returns the partitioning of the table of value from
+// SYS.PARTITIONED_TABLES, or nil if the table is not partitioned. Only the first level of
+// multi-level partitionings is returned, without the ranges of RANGE partitions.
+func (m Migrator) TablePartitioning(value interface{}) (*Partitioning, error) {
+	var partitioning *Partitioning
+	err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		var (
+			partitionType, expression sql.NullString
+			count                     sql.NullInt64
+			schemaName, table         = m.resolveTable(stmt)
+		)
+		err := m.DB.Raw(
+			"SELECT LEVEL_1_TYPE, LEVEL_1_EXPRESSION, LEVEL_1_COUNT FROM SYS.PARTITIONED_TABLES WHERE SCHEMA_NAME = ? AND TABLE_NAME = ?",
+			schemaName, table,
+		).Row().Scan(&partitionType, &expression, &count)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		partitioning = &Partitioning{Type: PartitionType(partitionType.String)}
+		if partitioning.Type != RangePartitioning {
+			partitioning.Partitions = int(count.Int64)
+		}
+		for _, column := range strings.Split(expression.String, ",") {
+			if column = strings.Trim(strings.TrimSpace(column), `"`); column != "" {
+				partitioning.Columns = append(partitioning.Columns, m.fieldDBName(stmt, column))
+			}
+		}
+		return nil
+	})
+	return partitioning, err
+}
+
+// TablePartition is a partition of a table as listed by Partitions. Others is set for the
+// RANGE partition of the rows outside the ranges of the other partitions.
+type TablePartition struct {
+	ID int
+	PartitionRange
+	Others bool
+}
+
+// Partitions returns the partitions of the table of value from SYS.TABLE_PARTITIONS in order
+// of their ID, with their ranges if the table is partitioned by RANGE.
+func (m Migrator) Partitions(value interface{}) (partitions []TablePartition, err error) {
+	partitioning, err := m.TablePartitioning(value)
+	if err != nil || partitioning == nil {
+		return nil, err
+	}
+
+	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		schemaName, table := m.resolveTable(stmt)
+		rows, err := m.DB.Raw(
+			"SELECT PART_ID, LEVEL_1_RANGE_MIN_VALUE, LEVEL_1_RANGE_MAX_VALUE FROM SYS.TABLE_PARTITIONS WHERE SCHEMA_NAME = ? AND TABLE_NAME = ? ORDER BY PART_ID",
+			schemaName, table,
+		).Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				partition    TablePartition
+				lower, upper sql.NullString
+			)
+			if err := rows.Scan(&partition.ID, &lower, &upper); err != nil {
+				return err
+			}
+
+			switch {
+			case partitioning.Type != RangePartitioning:
+			case lower.String == "" && upper.String == "":
+				partition.Others = true
+			case upper.String == "":
+				partition.Value = lower.String
+			default:
+				partition.Min, partition.Max = lower.String, upper.String
+			}
+			partitions = append(partitions, partition)
+		}
+		return rows.Err()
+	})
+	return partitions, err
+}
+
+// AddPartition adds a partition to a table partitioned by RANGE, like the partition of the
+// next month of a log table:
+//
+//	m.AddPartition(&Log{}, hdb.PartitionRange{Min: "2024-03-01", Max: "2024-04-01"})
+//	// ALTER TABLE "LOGS" ADD PARTITION '2024-03-01' <= VALUES < '2024-04-01'
+func (m Migrator) AddPartition(value interface{}, partitionRange PartitionRange) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.DB.Exec("ALTER TABLE ? ADD "+partitionRange.spec(), m.CurrentTable(stmt)).Error
+	})
+}
+
+// DropPartition drops a partition of a table partitioned by RANGE with the rows in it.
+func (m Migrator) DropPartition(value interface{}, partitionRange PartitionRange) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.DB.Exec("ALTER TABLE ? DROP "+partitionRange.spec(), m.CurrentTable(stmt)).Error
+	})
+}
+
+// MovePartition moves the partition with the ID of Partitions to another index server,
+// given as host:port.
+func (m Migrator) MovePartition(value interface{}, id int, location string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.DB.Exec(
+			"ALTER TABLE ? MOVE PARTITION "+strconv.Itoa(id)+" TO "+quoteString(location), m.CurrentTable(stmt),
+		).Error
+	})
+}
+
+// MergePartitions merges the partitions of a table, which is no longer partitioned after.
+func (m Migrator) MergePartitions(value interface{}) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.DB.Exec("ALTER TABLE ? MERGE PARTITIONS", m.CurrentTable(stmt)).Error
+	})
+}
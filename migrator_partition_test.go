@@ -0,0 +1,79 @@
+package hdb
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Event struct {
+	ID        uint
+	CreatedAt string
+}
+
+func TestPartitionClause(t *testing.T) {
+	tests := []struct {
+		name         string
+		partitioning Partitioning
+		sql          string
+		err          bool
+	}{
+		{
+			name:         "hash",
+			partitioning: Partitioning{Type: HashPartitioning, Columns: []string{"ID"}, Partitions: 4},
+			sql:          `PARTITION BY HASH ("ID") PARTITIONS 4`,
+		},
+		{
+			name:         "hash per host",
+			partitioning: Partitioning{Type: HashPartitioning, Columns: []string{"ID", "CreatedAt"}},
+			sql:          `PARTITION BY HASH ("ID","CREATED_AT") PARTITIONS GET_NUM_SERVERS()`,
+		},
+		{
+			name:         "round robin",
+			partitioning: Partitioning{Type: RoundRobinPartitioning, Partitions: 2},
+			sql:          `PARTITION BY ROUNDROBIN PARTITIONS 2`,
+		},
+		{
+			name: "range",
+			partitioning: Partitioning{
+				Type:    RangePartitioning,
+				Columns: []string{"CREATED_AT"},
+				Ranges:  []PartitionRange{{Min: "2024-01-01", Max: "2025-01-01"}, {Value: "2025-01-01"}},
+				Others:  true,
+			},
+			sql: `PARTITION BY RANGE ("CREATED_AT") (PARTITION '2024-01-01' <= VALUES < '2025-01-01', PARTITION VALUE = '2025-01-01', PARTITION OTHERS)`,
+		},
+		{name: "hash without columns", partitioning: Partitioning{Type: HashPartitioning}, err: true},
+		{name: "range without partitions", partitioning: Partitioning{Type: RangePartitioning, Columns: []string{"ID"}}, err: true},
+		{name: "unsupported", partitioning: Partitioning{Type: "LIST"}, err: true},
+	}
+
+	db := dryRunDB(t, Config{})
+	m := db.Migrator().(Migrator)
+	event := &gorm.Statement{DB: db}
+	if err := event.Parse(&Event{}); err != nil {
+		t.Fatalf("failed to parse Event: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, vars, err := m.partitionClause(event, tt.partitioning)
+			if tt.err {
+				if err == nil {
+					t.Errorf("got %s, want an error", sql)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to build partition clause: %v", err)
+			}
+
+			stmt := &gorm.Statement{DB: db}
+			clause.Expr{SQL: sql, Vars: vars}.Build(stmt)
+			if stmt.SQL.String() != tt.sql {
+				t.Errorf("got %s, want %s", stmt.SQL.String(), tt.sql)
+			}
+		})
+	}
+}
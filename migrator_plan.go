@@ -0,0 +1,219 @@
+package hdb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrDestructiveChange change may lose data and was not allowed
+var ErrDestructiveChange = errors.New("change may lose data")
+
+// ChangeKind is the kind of a change of a Plan.
+type ChangeKind string
+
+const (
+	ChangeCreateTable ChangeKind = "CreateTable"
+	ChangeAddColumn   ChangeKind = "AddColumn"
+	ChangeAlterType   ChangeKind = "AlterType"
+	ChangeAlterColumn ChangeKind = "AlterColumn"
+	ChangeDropColumn  ChangeKind = "DropColumn"
+	ChangeCreateIndex ChangeKind = "CreateIndex"
+	ChangeDropIndex   ChangeKind = "DropIndex"
+)
+
+// Change is a change of a table to match its model. Name is the column or index changed.
+// Destructive changes may lose data, like dropping a column or narrowing its type.
+type Change struct {
+	Kind        ChangeKind
+	Model       interface{}
+	Table       string
+	Name        string
+	Destructive bool
+}
+
+func (c Change) String() string {
+	s := string(c.Kind) + " " + c.Table
+	if c.Name != "" {
+		s += "." + c.Name
+	}
+	if c.Destructive {
+		s += " (destructive)"
+	}
+	return s
+}
+
+// Plan is the list of changes migrating the catalog to the models, in the order they apply.
+type Plan struct {
+	Changes []Change
+}
+
+// Destructive returns the changes of the plan that may lose data.
+func (p *Plan) Destructive() []Change {
+	var changes []Change
+	for _, change := range p.Changes {
+		if change.Destructive {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// ApplyOptions control how Apply runs a plan.
+type ApplyOptions struct {
+	// AllowDestructive runs changes that may lose data, which fail the plan otherwise.
+	AllowDestructive bool
+}
+
+// Plan compares the models with their tables in the catalog and returns the changes that
+// migrate them. Unlike AutoMigrate, the plan drops the columns and indexes a model lacks,
+// as destructive changes:
+//
+//	plan, err := m.Plan(&User{}, &Order{})
+//	for _, change := range plan.Changes {
+//		fmt.Println(change)
+//	}
+//	err = m.Apply(plan, hdb.ApplyOptions{})
+func (m Migrator) Plan(values ...interface{}) (*Plan, error) {
+	plan := &Plan{}
+	for _, value := range m.ReorderModels(values, true) {
+		if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+			if stmt.Schema == nil {
+				return errors.New("failed to get schema")
+			}
+
+			if !m.HasTable(value) {
+				plan.add(value, stmt.Table, ChangeCreateTable, "", false)
+				return nil
+			}
+			if err := m.planColumns(plan, value, stmt); err != nil {
+				return err
+			}
+			return m.planIndexes(plan, value, stmt)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+func (p *Plan) add(value interface{}, table string, kind ChangeKind, name string, destructive bool) {
+	p.Changes = append(p.Changes, Change{Kind: kind, Model: value, Table: table, Name: name, Destructive: destructive})
+}
+
+func (m Migrator) planColumns(plan *Plan, value interface{}, stmt *gorm.Statement) error {
+	columnTypes, err := m.ColumnTypes(value)
+	if err != nil {
+		return err
+	}
+
+	// the generated columns of index expressions are the indexes'
+	expressions := m.expressionColumns(stmt)
+	columns := make(map[string]gorm.ColumnType, len(columnTypes))
+	for _, columnType := range columnTypes {
+		if !expressions[m.NormalizeIdentifier(columnType.Name())] {
+			columns[columnType.Name()] = columnType
+		}
+	}
+
+	for _, dbName := range stmt.Schema.DBNames {
+		field := stmt.Schema.FieldsByDBName[dbName]
+		if field.IgnoreMigration || isFlexibleField(field) {
+			continue
+		}
+
+		columnType, ok := columns[dbName]
+		if !ok {
+			plan.add(value, stmt.Table, ChangeAddColumn, dbName, false)
+			continue
+		}
+		delete(columns, dbName)
+
+		if isGeneratedField(field) || isGenerated(columnType) {
+			continue
+		}
+		if diff := m.diffColumn(field, columnType); diff.typeChanged {
+			plan.add(value, stmt.Table, ChangeAlterType, dbName, diff.narrowed)
+		} else if diff.definitionChanged {
+			plan.add(value, stmt.Table, ChangeAlterColumn, dbName, false)
+		}
+	}
+
+	// the columns of schema-flexible tables missing from their model are dynamic
+	if flexibleFieldOf(stmt.Schema) != nil {
+		return nil
+	}
+	for _, columnType := range columnTypes {
+		if _, ok := columns[columnType.Name()]; ok {
+			plan.add(value, stmt.Table, ChangeDropColumn, columnType.Name(), true)
+		}
+	}
+	return nil
+}
+
+func (m Migrator) planIndexes(plan *Plan, value interface{}, stmt *gorm.Statement) error {
+	indexes, err := m.GetIndexes(value)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]bool, len(indexes))
+	for _, index := range indexes {
+		existing[index.Name()] = true
+	}
+
+	modelIndexes := stmt.Schema.ParseIndexes()
+	for _, idx := range modelIndexes {
+		if !existing[idx.Name] {
+			plan.add(value, stmt.Table, ChangeCreateIndex, idx.Name, false)
+		}
+	}
+
+	constraints, err := m.constraintNames(stmt)
+	if err != nil {
+		return err
+	}
+
+	// indexes of the primary key and constraints are named by HANA or after the constraint
+	for _, index := range indexes {
+		if primaryKey, _ := index.PrimaryKey(); primaryKey || strings.HasPrefix(index.Name(), "_SYS_") || constraints[index.Name()] {
+			continue
+		}
+		if _, ok := modelIndexes[index.Name()]; !ok {
+			plan.add(value, stmt.Table, ChangeDropIndex, index.Name(), true)
+		}
+	}
+	return nil
+}
+
+// Apply runs the changes of a plan in order. Plans with destructive changes fail with
+// ErrDestructiveChange unless AllowDestructive is set.
+func (m Migrator) Apply(plan *Plan, options ApplyOptions) error {
+	if destructive := plan.Destructive(); len(destructive) > 0 && !options.AllowDestructive {
+		return fmt.Errorf("%w: %s", ErrDestructiveChange, destructive[0])
+	}
+
+	for _, change := range plan.Changes {
+		var err error
+		switch change.Kind {
+		case ChangeCreateTable:
+			err = m.CreateTable(change.Model)
+		case ChangeAddColumn:
+			err = m.AddColumn(change.Model, change.Name)
+		case ChangeAlterType, ChangeAlterColumn:
+			err = m.AlterColumn(change.Model, change.Name)
+		case ChangeDropColumn:
+			err = m.DropColumn(change.Model, change.Name)
+		case ChangeCreateIndex:
+			err = m.CreateIndex(change.Model, change.Name)
+		case ChangeDropIndex:
+			err = m.DropIndex(change.Model, change.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
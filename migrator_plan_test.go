@@ -0,0 +1,101 @@
+package hdb
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type Planned struct {
+	ID    uint
+	Name  string `gorm:"size:100;index"`
+	Email string `gorm:"size:200"`
+}
+
+// scriptMigrator returns a migrator adding the statements it runs to script, on a session
+// without server that fails catalog queries.
+func scriptMigrator(t *testing.T, config Config, script *Script) Migrator {
+	t.Helper()
+	return openNoServer(t, config, &gorm.Config{}).Migrator().(Migrator).DryRun(script)
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name       string
+		changes    []Change
+		options    ApplyOptions
+		statements []string
+		err        error
+	}{
+		{
+			name:       "add column",
+			changes:    []Change{{Kind: ChangeAddColumn, Name: "email"}},
+			statements: []string{`ALTER TABLE "PLANNEDS" ADD ("EMAIL" NVARCHAR(200))`},
+		},
+		{
+			name:       "alter columns",
+			changes:    []Change{{Kind: ChangeAlterType, Name: "name"}, {Kind: ChangeAlterColumn, Name: "email"}},
+			statements: []string{`ALTER TABLE "PLANNEDS" ALTER ("NAME" NVARCHAR(100) NULL)`, `ALTER TABLE "PLANNEDS" ALTER ("EMAIL" NVARCHAR(200) NULL)`},
+		},
+		{
+			name:    "destructive",
+			changes: []Change{{Kind: ChangeAddColumn, Name: "email"}, {Kind: ChangeDropColumn, Name: "NICK", Destructive: true}},
+			err:     ErrDestructiveChange,
+		},
+		{
+			name:       "destructive allowed",
+			changes:    []Change{{Kind: ChangeDropColumn, Name: "NICK", Destructive: true}},
+			options:    ApplyOptions{AllowDestructive: true},
+			statements: []string{`ALTER TABLE "PLANNEDS" DROP ("NICK")`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := &Plan{}
+			for _, change := range tt.changes {
+				change.Model, change.Table = &Planned{}, "PLANNEDS"
+				plan.Changes = append(plan.Changes, change)
+			}
+
+			var script Script
+			err := scriptMigrator(t, Config{}, &script).Apply(plan, tt.options)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if !reflect.DeepEqual(script.Statements, tt.statements) {
+				t.Errorf("got %q, want %q", script.Statements, tt.statements)
+			}
+		})
+	}
+}
+
+func TestPlanDestructive(t *testing.T) {
+	plan := &Plan{Changes: []Change{
+		{Kind: ChangeAddColumn, Table: "PLANNEDS", Name: "email"},
+		{Kind: ChangeAlterType, Table: "PLANNEDS", Name: "NAME", Destructive: true},
+		{Kind: ChangeDropIndex, Table: "PLANNEDS", Name: "IDX_NICK", Destructive: true},
+	}}
+
+	destructive := plan.Destructive()
+	if len(destructive) != 2 || destructive[0].Name != "NAME" || destructive[1].Name != "IDX_NICK" {
+		t.Errorf("got %v, want the changes of NAME and IDX_NICK", destructive)
+	}
+	if s := destructive[0].String(); s != "AlterType PLANNEDS.NAME (destructive)" {
+		t.Errorf("got %s, want AlterType PLANNEDS.NAME (destructive)", s)
+	}
+}
+
+func TestPlanCreateTable(t *testing.T) {
+	// a session without server finds no tables in the catalog
+	m := openNoServer(t, Config{DefaultSchema: "APP"}, &gorm.Config{}).Migrator().(Migrator)
+	plan, err := m.Plan(&Planned{})
+	if err != nil {
+		t.Fatalf("failed to plan: %v", err)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0].String() != "CreateTable planneds" {
+		t.Errorf("got %v, want CreateTable planneds", plan.Changes)
+	}
+}
@@ -0,0 +1,120 @@
+package hdb
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// CreateProcedure creates or replaces the SQLScript procedure name with definition, the
+// procedure's parameters, options and body following its name:
+//
+//	const archiveOrders = `(IN before DATE) LANGUAGE SQLSCRIPT AS
+//	BEGIN
+//		INSERT INTO ORDERS_ARCHIVE SELECT * FROM ORDERS WHERE CREATED_AT < :before;
+//		DELETE FROM ORDERS WHERE CREATED_AT < :before;
+//	END`
+//
+//	m.CreateProcedure("ARCHIVE_ORDERS", archiveOrders)
+//
+// The grantees of Config.Grants are granted EXECUTE on it. HANA 1.0 has no CREATE OR
+// REPLACE, existing procedures are dropped and created again there, which leaves the
+// objects using them invalid until they are.
+func (m Migrator) CreateProcedure(name, definition string) error {
+	return m.createRoutine("PROCEDURE", name, definition, m.HasProcedure)
+}
+
+// CreateFunction creates or replaces the user-defined function name with definition like
+// CreateProcedure, the function's parameters, return type and body following its name.
+func (m Migrator) CreateFunction(name, definition string) error {
+	return m.createRoutine("FUNCTION", name, definition, m.HasFunction)
+}
+
+func (m Migrator) createRoutine(kind, name, definition string, exists func(string) bool) error {
+	createSQL := "CREATE OR REPLACE " + kind + " ? ?"
+	if m.DontSupportReplaceProcedure {
+		if exists(name) {
+			if err := m.DB.Exec("DROP "+kind+" ?", clause.Table{Name: name}).Error; err != nil {
+				return err
+			}
+		}
+		createSQL = "CREATE " + kind + " ? ?"
+	}
+	// the definition is taken as it is, question marks and all
+	if err := m.DB.Exec(createSQL, clause.Table{Name: name}, clause.Expr{SQL: strings.TrimSpace(definition)}).Error; err != nil {
+		return err
+	}
+	return m.grantExecute(name)
+}
+
+// DropProcedure drops the procedure name if it exists. It fails without dropping it if
+// other objects use the procedure, which HANA would leave invalid.
+func (m Migrator) DropProcedure(name string) error {
+	if !m.HasProcedure(name) {
+		return nil
+	}
+	return m.dropRoutine("PROCEDURE", name)
+}
+
+// DropFunction drops the function name if it exists. It fails without dropping it if other
+// objects use the function, which HANA would leave invalid.
+func (m Migrator) DropFunction(name string) error {
+	if !m.HasFunction(name) {
+		return nil
+	}
+	return m.dropRoutine("FUNCTION", name)
+}
+
+func (m Migrator) dropRoutine(kind, name string) error {
+	dependents, err := m.Dependents(name)
+	if err != nil {
+		return err
+	}
+	if len(dependents) > 0 {
+		return fmt.Errorf("%s %s is used by %s", strings.ToLower(kind), name, strings.Join(dependents, ", "))
+	}
+	return m.DB.Exec("DROP "+kind+" ?", clause.Table{Name: name}).Error
+}
+
+// HasProcedure checks whether the procedure name exists in SYS.PROCEDURES.
+func (m Migrator) HasProcedure(name string) bool {
+	var count int64
+	schemaName, procedure := m.CurrentSchema(m.DB.Statement, name)
+	m.DB.Raw("SELECT COUNT(*) FROM SYS.PROCEDURES WHERE SCHEMA_NAME = ? AND PROCEDURE_NAME = ?", schemaName, procedure).Row().Scan(&count)
+	return count > 0
+}
+
+// HasFunction checks whether the function name exists in SYS.FUNCTIONS.
+func (m Migrator) HasFunction(name string) bool {
+	var count int64
+	schemaName, function := m.CurrentSchema(m.DB.Statement, name)
+	m.DB.Raw("SELECT COUNT(*) FROM SYS.FUNCTIONS WHERE SCHEMA_NAME = ? AND FUNCTION_NAME = ?", schemaName, function).Row().Scan(&count)
+	return count > 0
+}
+
+// Dependents returns the schema qualified names of the objects directly using the object
+// name, like the procedures calling a procedure or the views selecting from a table, from
+// SYS.OBJECT_DEPENDENCIES.
+func (m Migrator) Dependents(name string) ([]string, error) {
+	schemaName, object := m.CurrentSchema(m.DB.Statement, name)
+	rows, err := m.DB.Raw(
+		"SELECT DEPENDENT_SCHEMA_NAME, DEPENDENT_OBJECT_NAME FROM SYS.OBJECT_DEPENDENCIES "+
+			"WHERE BASE_SCHEMA_NAME = ? AND BASE_OBJECT_NAME = ? AND DEPENDENCY_TYPE = 1 ORDER BY DEPENDENT_SCHEMA_NAME, DEPENDENT_OBJECT_NAME",
+		schemaName, object,
+	).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dependents []string
+	for rows.Next() {
+		var dependentSchema, dependent string
+		if err := rows.Scan(&dependentSchema, &dependent); err != nil {
+			return nil, err
+		}
+		dependents = append(dependents, dependentSchema+"."+dependent)
+	}
+	return dependents, rows.Err()
+}
@@ -0,0 +1,32 @@
+package hdb
+
+import (
+	"gorm.io/gorm/clause"
+)
+
+// CreateSchema creates the schema name, optionally owned by another user.
+func (m Migrator) CreateSchema(name string, owner ...string) error {
+	if len(owner) > 0 && owner[0] != "" {
+		return m.DB.Exec("CREATE SCHEMA ? OWNED BY ?", clause.Table{Name: name}, clause.Table{Name: owner[0]}).Error
+	}
+	return m.DB.Exec("CREATE SCHEMA ?", clause.Table{Name: name}).Error
+}
+
+// DropSchema drops the schema name including all objects in it.
+func (m Migrator) DropSchema(name string) error {
+	return m.DB.Exec("DROP SCHEMA ? CASCADE", clause.Table{Name: name}).Error
+}
+
+// HasSchema checks whether the schema name exists.
+func (m Migrator) HasSchema(name string) bool {
+	var count int64
+	m.DB.Raw("SELECT COUNT(*) FROM SYS.SCHEMAS WHERE SCHEMA_NAME = ?", m.NormalizeIdentifier(name)).Row().Scan(&count)
+	return count > 0
+}
+
+// InSchema returns a migrator that creates, alters and inspects tables with unqualified
+// names in schema instead of the connection's default schema.
+func (m Migrator) InSchema(schema string) Migrator {
+	db := m.DB.WithContext(WithSchema(m.DB.Statement.Context, schema))
+	return m.Dialector.Migrator(db).(Migrator)
+}
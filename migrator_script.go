@@ -0,0 +1,63 @@
+package hdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Script collects the statements of a migrator in dry-run mode, in the order the migrator
+// would run them.
+type Script struct {
+	Statements []string
+}
+
+// String returns the statements as a script, each terminated by a semicolon.
+func (s *Script) String() string {
+	var b strings.Builder
+	for _, statement := range s.Statements {
+		b.WriteString(statement)
+		b.WriteString(";\n")
+	}
+	return b.String()
+}
+
+// DryRun returns a migrator that adds the DDL statements it would run to script instead
+// of running them. Catalog queries still run, so the script holds the changes to the
+// current database:
+//
+//	var script hdb.Script
+//	err := db.Migrator().(hdb.Migrator).DryRun(&script).AutoMigrate(&User{})
+//	fmt.Print(script.String())
+func (m Migrator) DryRun(script *Script) Migrator {
+	db := m.DB.Session(&gorm.Session{})
+	db.Statement.ConnPool = scriptConnPool{ConnPool: db.Statement.ConnPool, script: script, dialector: m.Dialector}
+	return m.Dialector.Migrator(db).(Migrator)
+}
+
+// scriptConnPool runs queries and adds the statements executed to its script.
+type scriptConnPool struct {
+	gorm.ConnPool
+	script    *Script
+	dialector Dialector
+}
+
+func (p scriptConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	p.script.Statements = append(p.script.Statements, p.dialector.Explain(query, args...))
+	return scriptResult{}, nil
+}
+
+// scriptResult is the result of a statement added to a script.
+type scriptResult struct{}
+
+func (scriptResult) LastInsertId() (int64, error) { return 0, nil }
+func (scriptResult) RowsAffected() (int64, error) { return 0, nil }
+
+// missingInDryRun reports whether a catalog query of an existing table's settings found no
+// row, as for the tables a dry run would create, which are missing from the catalog.
+func missingInDryRun(err error) bool {
+	return errors.Is(err, sql.ErrNoRows)
+}
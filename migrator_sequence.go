@@ -0,0 +1,123 @@
+package hdb
+
+import (
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// createSequences creates the missing sequences backing fields of the statement's model.
+func (m Migrator) createSequences(stmt *gorm.Statement) error {
+	for _, field := range stmt.Schema.Fields {
+		if sequence := sequenceOf(field); sequence != "" && !m.hasSequence(stmt, sequence) {
+			if err := m.DB.Exec("CREATE SEQUENCE ?", sequenceTable(stmt, sequence)).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dropSequences drops the sequences backing fields of the statement's model.
+func (m Migrator) dropSequences(tx *gorm.DB, stmt *gorm.Statement) error {
+	for _, field := range stmt.Schema.Fields {
+		if sequence := sequenceOf(field); sequence != "" && m.hasSequence(stmt, sequence) {
+			if err := tx.Exec("DROP SEQUENCE ?", sequenceTable(stmt, sequence)).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m Migrator) hasSequence(stmt *gorm.Statement, name string) bool {
+	return m.HasSequence(sequenceTable(stmt, name).Name)
+}
+
+// SequenceOptions are the options of a sequence, which HANA defaults where unset.
+type SequenceOptions struct {
+	// StartWith is the first value of the sequence, which AlterSequence restarts it with.
+	StartWith *int64
+	// IncrementBy is the step between the values of the sequence, which is 1 by default.
+	IncrementBy int64
+	MinValue    *int64
+	MaxValue    *int64
+	// Cache is the number of values cached ahead, negative for NO CACHE.
+	Cache int
+	// Cycle sets whether the sequence starts over at its minimum value after its maximum.
+	Cycle *bool
+	// ResetBy is the query of the value the sequence restarts with when the database does,
+	// like SELECT MAX(ID) + 1 FROM INVOICES.
+	ResetBy string
+}
+
+// clauses returns the options as CREATE or ALTER SEQUENCE clauses.
+func (o SequenceOptions) clauses(start string) string {
+	var clauses []string
+	if o.StartWith != nil {
+		clauses = append(clauses, start+" WITH "+strconv.FormatInt(*o.StartWith, 10))
+	}
+	if o.IncrementBy != 0 {
+		clauses = append(clauses, "INCREMENT BY "+strconv.FormatInt(o.IncrementBy, 10))
+	}
+	if o.MinValue != nil {
+		clauses = append(clauses, "MINVALUE "+strconv.FormatInt(*o.MinValue, 10))
+	}
+	if o.MaxValue != nil {
+		clauses = append(clauses, "MAXVALUE "+strconv.FormatInt(*o.MaxValue, 10))
+	}
+	switch {
+	case o.Cache > 0:
+		clauses = append(clauses, "CACHE "+strconv.Itoa(o.Cache))
+	case o.Cache < 0:
+		clauses = append(clauses, "NO CACHE")
+	}
+	if o.Cycle != nil {
+		if *o.Cycle {
+			clauses = append(clauses, "CYCLE")
+		} else {
+			clauses = append(clauses, "NO CYCLE")
+		}
+	}
+	if o.ResetBy != "" {
+		clauses = append(clauses, "RESET BY "+o.ResetBy)
+	}
+	return strings.Join(clauses, " ")
+}
+
+// CreateSequence creates the sequence name, which may be qualified with a schema:
+//
+//	start := int64(1000)
+//	m.CreateSequence("INVOICE_NO", hdb.SequenceOptions{StartWith: &start, ResetBy: "SELECT MAX(NO) + 1 FROM INVOICES"})
+//	// CREATE SEQUENCE "INVOICE_NO" START WITH 1000 RESET BY SELECT MAX(NO) + 1 FROM INVOICES
+func (m Migrator) CreateSequence(name string, options SequenceOptions) error {
+	return m.DB.Exec(strings.TrimSpace("CREATE SEQUENCE ? "+options.clauses("START")), clause.Table{Name: name}).Error
+}
+
+// AlterSequence changes the options of the sequence name that are set, restarting it with
+// StartWith if set.
+func (m Migrator) AlterSequence(name string, options SequenceOptions) error {
+	clauses := options.clauses("RESTART")
+	if clauses == "" {
+		return nil
+	}
+	return m.DB.Exec("ALTER SEQUENCE ? "+clauses, clause.Table{Name: name}).Error
+}
+
+// DropSequence drops the sequence name if it exists.
+func (m Migrator) DropSequence(name string) error {
+	if !m.HasSequence(name) {
+		return nil
+	}
+	return m.DB.Exec("DROP SEQUENCE ?", clause.Table{Name: name}).Error
+}
+
+// HasSequence checks whether the sequence name exists in SYS.SEQUENCES.
+func (m Migrator) HasSequence(name string) bool {
+	var count int64
+	schemaName, sequence := m.CurrentSchema(m.DB.Statement, name)
+	m.DB.Raw("SELECT COUNT(*) FROM SYS.SEQUENCES WHERE SCHEMA_NAME = ? AND SEQUENCE_NAME = ?", schemaName, sequence).Row().Scan(&count)
+	return count > 0
+}
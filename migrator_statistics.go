@@ -0,0 +1,129 @@
+package hdb
+
+import (
+	"database/sql"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StatisticsType is the kind of data statistics the optimizer estimates selectivities with.
+type StatisticsType string
+
+const (
+	HistogramStatistics   StatisticsType = "HISTOGRAM"
+	SimpleStatistics      StatisticsType = "SIMPLE"
+	TopKStatistics        StatisticsType = "TOPK"
+	SketchStatistics      StatisticsType = "SKETCH"
+	SampleStatistics      StatisticsType = "SAMPLE"
+	RecordCountStatistics StatisticsType = "RECORD COUNT"
+)
+
+// Statistics is a data statistics object on columns of a table, which the optimizer lacks
+// for virtual tables and row tables without it.
+type Statistics struct {
+	// Name names the statistics object, which HANA names itself if empty.
+	Name string
+	// Fields are the fields or columns of the statistics, none for RECORD COUNT.
+	Fields []string
+	// Type is the kind of statistics, HISTOGRAM by default.
+	Type StatisticsType
+}
+
+// statisticsTarget returns the statistics object as the ON clause of CREATE, REFRESH or DROP
+// STATISTICS with the columns of stmt's model, or by name if named and byName is set.
+func (m Migrator) statisticsTarget(stmt *gorm.Statement, statistics Statistics, byName bool) (string, []interface{}) {
+	if byName && statistics.Name != "" {
+		return "?", []interface{}{sequenceTable(stmt, statistics.Name)}
+	}
+
+	target, vars := "ON ?", []interface{}{m.CurrentTable(stmt)}
+	if len(statistics.Fields) > 0 {
+		columns := make([]interface{}, 0, len(statistics.Fields))
+		for _, name := range statistics.Fields {
+			if stmt.Schema != nil {
+				if field := stmt.Schema.LookUpField(name); field != nil {
+					name = field.DBName
+				}
+			}
+			columns = append(columns, clause.Column{Name: name})
+		}
+		target += " ?"
+		vars = append(vars, columns)
+	}
+	if statistics.Type != "" {
+		target += " TYPE " + string(statistics.Type)
+	}
+	return target, vars
+}
+
+// CreateStatistics creates a data statistics object on the table of value:
+//
+//	m.CreateStatistics(&Customer{}, hdb.Statistics{Fields: []string{"Country"}, Type: hdb.TopKStatistics})
+//	// CREATE STATISTICS ON "CUSTOMERS" ("COUNTRY") TYPE TOPK
+func (m Migrator) CreateStatistics(value interface{}, statistics Statistics) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		createSQL, vars := "CREATE STATISTICS ", []interface{}{}
+		if statistics.Name != "" {
+			createSQL += "? "
+			vars = append(vars, sequenceTable(stmt, statistics.Name))
+		}
+		target, targetVars := m.statisticsTarget(stmt, statistics, false)
+		return m.DB.Exec(createSQL+target, append(vars, targetVars...)...).Error
+	})
+}
+
+// RefreshStatistics refreshes a data statistics object on the table of value with its
+// current data, the named object or else those on the columns of statistics.
+func (m Migrator) RefreshStatistics(value interface{}, statistics Statistics) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		target, vars := m.statisticsTarget(stmt, statistics, true)
+		return m.DB.Exec("REFRESH STATISTICS "+target, vars...).Error
+	})
+}
+
+// DropStatistics drops a data statistics object on the table of value, the named object or
+// else those on the columns of statistics, all of the table's without columns and type.
+func (m Migrator) DropStatistics(value interface{}, statistics Statistics) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		target, vars := m.statisticsTarget(stmt, statistics, true)
+		return m.DB.Exec("DROP STATISTICS "+target, vars...).Error
+	})
+}
+
+// GetStatistics returns the data statistics objects on the table of value from
+// SYS.DATA_STATISTICS, with the columns as fields' DBNames.
+func (m Migrator) GetStatistics(value interface{}) (result []Statistics, err error) {
+	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		schemaName, table := m.resolveTable(stmt)
+		rows, err := m.DB.Raw(
+			"SELECT DATA_STATISTICS_NAME, DATA_SOURCE_COLUMN_NAMES, DATA_STATISTICS_TYPE FROM SYS.DATA_STATISTICS "+
+				"WHERE DATA_SOURCE_SCHEMA_NAME = ? AND DATA_SOURCE_OBJECT_NAME = ? ORDER BY DATA_STATISTICS_NAME",
+			schemaName, table,
+		).Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				name, columns sql.NullString
+				statistics    Statistics
+			)
+			if err := rows.Scan(&name, &columns, &statistics.Type); err != nil {
+				return err
+			}
+			statistics.Name = name.String
+			if columns.String != "" {
+				for _, column := range strings.Split(columns.String, ",") {
+					statistics.Fields = append(statistics.Fields, m.fieldDBName(stmt, strings.TrimSpace(column)))
+				}
+			}
+			result = append(result, statistics)
+		}
+		return rows.Err()
+	})
+	return result, err
+}
@@ -0,0 +1,111 @@
+package hdb
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateSynonym creates the private synonym name for the table of value, a model or table name.
+func (m Migrator) CreateSynonym(name string, value interface{}) error {
+	return m.createSynonym("CREATE SYNONYM ? FOR ?", name, value)
+}
+
+// CreatePublicSynonym creates the public synonym name for the table of value, a model or table name.
+func (m Migrator) CreatePublicSynonym(name string, value interface{}) error {
+	return m.createSynonym("CREATE PUBLIC SYNONYM ? FOR ?", name, value)
+}
+
+func (m Migrator) createSynonym(sql, name string, value interface{}) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.DB.Exec(sql, clause.Table{Name: name}, m.CurrentTable(stmt)).Error
+	})
+}
+
+// DropSynonym drops the private synonym name.
+func (m Migrator) DropSynonym(name string) error {
+	return m.DB.Exec("DROP SYNONYM ?", clause.Table{Name: name}).Error
+}
+
+// DropPublicSynonym drops the public synonym name.
+func (m Migrator) DropPublicSynonym(name string) error {
+	return m.DB.Exec("DROP PUBLIC SYNONYM ?", clause.Table{Name: name}).Error
+}
+
+// HasSynonym checks whether a private synonym name exists in the current schema or a
+// public synonym name exists.
+func (m Migrator) HasSynonym(name string) bool {
+	var count int64
+	schemaName, synonym := m.CurrentSchema(m.DB.Statement, name)
+	m.DB.Raw(
+		"SELECT COUNT(*) FROM SYS.SYNONYMS WHERE SCHEMA_NAME IN (?, 'PUBLIC') AND SYNONYM_NAME = ?",
+		schemaName, synonym,
+	).Row().Scan(&count)
+	return count > 0
+}
+
+// resolveTable returns the schema and name of the statement's table, following a private
+// or public synonym to its base table when there is no table of that name.
+func (m Migrator) resolveTable(stmt *gorm.Statement) (string, string) {
+	schemaName, table := m.CurrentSchema(stmt, m.fullTable(stmt))
+
+	var count int64
+	m.DB.Raw("SELECT COUNT(*) FROM SYS.TABLES WHERE SCHEMA_NAME = ? AND TABLE_NAME = ?", schemaName, table).Row().Scan(&count)
+	if count > 0 {
+		return schemaName, table
+	}
+
+	var objectSchema, objectName string
+	row := m.DB.Raw(
+		"SELECT OBJECT_SCHEMA, OBJECT_NAME FROM SYS.SYNONYMS WHERE SCHEMA_NAME IN (?, 'PUBLIC') AND SYNONYM_NAME = ? AND OBJECT_TYPE = 'TABLE' ORDER BY CASE WHEN SCHEMA_NAME = 'PUBLIC' THEN 1 ELSE 0 END LIMIT 1",
+		schemaName, table,
+	).Row()
+	if err := row.Scan(&objectSchema, &objectName); err == nil {
+		m.debug("synonym schema=%s synonym=%s table=%s.%s", schemaName, table, objectSchema, objectName)
+		return objectSchema, objectName
+	}
+	return schemaName, table
+}
+
+// synonym is a private synonym, or a public synonym with the schema PUBLIC.
+type synonym struct {
+	Schema string
+	Name   string
+}
+
+// synonymsOf returns the synonyms for a table.
+func (m Migrator) synonymsOf(schemaName, table string) ([]synonym, error) {
+	rows, err := m.DB.Raw(
+		"SELECT SCHEMA_NAME, SYNONYM_NAME FROM SYS.SYNONYMS WHERE OBJECT_SCHEMA = ? AND OBJECT_NAME = ? AND OBJECT_TYPE = 'TABLE'",
+		schemaName, table,
+	).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var synonyms []synonym
+	for rows.Next() {
+		var s synonym
+		if err := rows.Scan(&s.Schema, &s.Name); err != nil {
+			return nil, err
+		}
+		synonyms = append(synonyms, s)
+	}
+	return synonyms, rows.Err()
+}
+
+// recreateSynonym replaces a synonym by one for table.
+func (m Migrator) recreateSynonym(s synonym, table clause.Table) error {
+	if s.Schema == "PUBLIC" {
+		if err := m.DB.Exec("DROP PUBLIC SYNONYM ?", clause.Table{Name: s.Name}).Error; err != nil {
+			return err
+		}
+		return m.DB.Exec("CREATE PUBLIC SYNONYM ? FOR ?", clause.Table{Name: s.Name}, table).Error
+	}
+
+	name := clause.Table{Name: s.Schema + "." + s.Name}
+	if err := m.DB.Exec("DROP SYNONYM ?", name).Error; err != nil {
+		return err
+	}
+	return m.DB.Exec("CREATE SYNONYM ? FOR ?", name, table).Error
+}
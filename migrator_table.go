@@ -0,0 +1,319 @@
+package hdb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TableStore is the store a table keeps its rows in.
+type TableStore string
+
+const (
+	ColumnStore TableStore = "COLUMN"
+	RowStore    TableStore = "ROW"
+)
+
+// TableStorer sets the store of a model's table, which is created as COLUMN table unless
+// the model implements TableStorer.
+type TableStorer interface {
+	TableStore() TableStore
+}
+
+// tableStoreOf returns the store of the statement's model, if it implements TableStorer.
+func tableStoreOf(stmt *gorm.Statement) (TableStore, bool) {
+	if stmt.Schema == nil {
+		return "", false
+	}
+	if storer, ok := reflect.New(stmt.Schema.ModelType).Interface().(TableStorer); ok {
+		return storer.TableStore(), true
+	}
+	return "", false
+}
+
+// GlobalTemporaryTabler makes a model's table a global temporary table if GlobalTemporary
+// returns true, whose definition is shared by all sessions while its rows are visible to
+// the session inserting them only, and deleted when it ends.
+type GlobalTemporaryTabler interface {
+	GlobalTemporary() bool
+}
+
+// globalTemporaryOf reports whether the statement's model is a global temporary table, if
+// it implements GlobalTemporaryTabler.
+func globalTemporaryOf(stmt *gorm.Statement) (bool, bool) {
+	if stmt.Schema == nil {
+		return false, false
+	}
+	if tabler, ok := reflect.New(stmt.Schema.ModelType).Interface().(GlobalTemporaryTabler); ok {
+		return tabler.GlobalTemporary(), true
+	}
+	return false, false
+}
+
+// tableTypeOf returns the table type of the statement's model for CREATE TABLE, like COLUMN
+// or GLOBAL TEMPORARY COLUMN.
+func tableTypeOf(stmt *gorm.Statement) string {
+	store := ColumnStore
+	if tableStore, ok := tableStoreOf(stmt); ok && tableStore != "" {
+		store = tableStore
+	}
+
+	if temporary, _ := globalTemporaryOf(stmt); temporary {
+		// global temporary tables are row tables unless stated otherwise
+		if store == RowStore {
+			return "GLOBAL TEMPORARY"
+		}
+		return "GLOBAL TEMPORARY " + string(store)
+	}
+	return string(store)
+}
+
+// createTable creates the table of the statement's model as a table of tableType with its
+// columns, primary key and constraints, followed by its indexes, as HANA takes no indexes in
+// CREATE TABLE.
+func (m Migrator) createTable(stmt *gorm.Statement, tableType string) error {
+	if stmt.Schema == nil {
+		return errors.New("failed to get schema")
+	}
+
+	var (
+		createTableSQL          = "CREATE " + tableType + " TABLE ? ("
+		values                  = []interface{}{m.CurrentTable(stmt)}
+		hasPrimaryKeyInDataType bool
+	)
+
+	for _, dbName := range stmt.Schema.DBNames {
+		field := stmt.Schema.FieldsByDBName[dbName]
+		if !field.IgnoreMigration && !isFlexibleField(field) {
+			createTableSQL += "? ?,"
+			hasPrimaryKeyInDataType = hasPrimaryKeyInDataType || strings.Contains(strings.ToUpper(m.Dialector.DataTypeOf(field)), "PRIMARY KEY")
+			values = append(values, clause.Column{Name: dbName}, m.DB.Migrator().FullDataTypeOf(field))
+		}
+	}
+
+	period, versioning, err := m.systemVersioning(stmt)
+	if err != nil {
+		return err
+	}
+	if period.SQL != "" {
+		createTableSQL += "?,"
+		values = append(values, period)
+	}
+
+	if validFrom, validTo := applicationTimeFields(stmt.Schema); validFrom != nil && validTo != nil {
+		createTableSQL += "PERIOD FOR APPLICATION_TIME (?, ?),"
+		values = append(values, clause.Column{Name: validFrom.DBName}, clause.Column{Name: validTo.DBName})
+	}
+
+	if !hasPrimaryKeyInDataType && len(stmt.Schema.PrimaryFields) > 0 {
+		createTableSQL += "PRIMARY KEY ?,"
+		primaryKeys := make([]interface{}, 0, len(stmt.Schema.PrimaryFields))
+		for _, field := range stmt.Schema.PrimaryFields {
+			primaryKeys = append(primaryKeys, clause.Column{Name: field.DBName})
+		}
+		values = append(values, primaryKeys)
+	}
+
+	// temporary tables take no foreign keys
+	if !strings.Contains(tableType, "TEMPORARY") {
+		for _, constraint := range m.foreignKeysOf(stmt) {
+			sql, vars, err := buildForeignKey(constraint)
+			if err != nil {
+				return err
+			}
+			createTableSQL += sql + ","
+			values = append(values, vars...)
+		}
+	}
+
+	for _, uni := range stmt.Schema.ParseUniqueConstraints() {
+		createTableSQL += "CONSTRAINT ? UNIQUE (?),"
+		values = append(values, clause.Column{Name: uni.Name}, clause.Column{Name: uni.Field.DBName})
+	}
+
+	for _, chk := range stmt.Schema.ParseCheckConstraints() {
+		createTableSQL += "CONSTRAINT ? CHECK (?),"
+		values = append(values, clause.Column{Name: chk.Name}, clause.Expr{SQL: m.checkCondition(chk.Constraint)})
+	}
+
+	createTableSQL = strings.TrimSuffix(createTableSQL, ",") + ")"
+
+	// local temporary tables take no masks
+	if mask := maskClause(stmt); mask.SQL != "" && !strings.HasPrefix(tableType, "LOCAL TEMPORARY") {
+		createTableSQL += " ?"
+		values = append(values, mask)
+	}
+
+	if versioning.SQL != "" {
+		createTableSQL += " ?"
+		values = append(values, versioning)
+	}
+
+	if flexibleFieldOf(stmt.Schema) != nil {
+		createTableSQL += " WITH SCHEMA FLEXIBILITY"
+	}
+
+	if unit, ok := tableLoadUnitOf(stmt); ok && unit != "" && !m.DontSupportNSE {
+		createTableSQL += " " + unit.loadable()
+	}
+
+	if options, ok := tableOptionsOf(stmt); ok {
+		if options.UnloadPriority != nil {
+			if err := validateUnloadPriority(*options.UnloadPriority); err != nil {
+				return err
+			}
+		}
+		createTableSQL += " " + options.clauses()
+	}
+
+	if partitioning, ok := tablePartitioningOf(stmt); ok {
+		partitionSQL, vars, err := m.partitionClause(stmt, partitioning)
+		if err != nil {
+			return err
+		}
+		createTableSQL += " " + partitionSQL
+		values = append(values, vars...)
+	}
+
+	if tableOption, ok := m.DB.Get("gorm:table_options"); ok {
+		createTableSQL += " " + strings.TrimSpace(fmt.Sprint(tableOption))
+	}
+
+	if err := m.DB.Exec(createTableSQL, values...).Error; err != nil {
+		return err
+	}
+
+	// indexes are named in the schema, which sessions creating local temporary tables of
+	// the same model would clash in
+	if strings.HasPrefix(tableType, "LOCAL TEMPORARY") {
+		return nil
+	}
+
+	indexes := stmt.Schema.ParseIndexes()
+	names := make([]string, 0, len(indexes))
+	for name := range indexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		idx := indexes[name]
+		if err := m.createIndex(stmt, &idx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AutoMigrate migrates the models like gorm does and then updates the comments, options,
+// load units, column masks, fuzzy search indexes, checks and foreign key actions of their
+// tables, warning about tables of another type than their model's. The definition of
+// global temporary tables is migrated like any other, which fails while sessions hold rows
+// in them. The virtual tables of models implementing VirtualTabler are created if missing,
+// and the views of models implementing Viewer after the tables. The privileges of
+// Config.Grants, or of models implementing Granter, are granted on all.
+func (m Migrator) AutoMigrate(values ...interface{}) error {
+	var (
+		tables        []interface{}
+		views         []interface{}
+		virtualTables []interface{}
+	)
+	for _, value := range values {
+		if _, ok := m.viewOf(value); ok {
+			views = append(views, value)
+		} else if m.isVirtualTable(value) {
+			virtualTables = append(virtualTables, value)
+		} else {
+			tables = append(tables, value)
+		}
+	}
+
+	if err := m.Migrator.AutoMigrate(tables...); err != nil {
+		return err
+	}
+
+	for _, value := range tables {
+		if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+			if err := m.migrateTableComment(stmt); err != nil {
+				return err
+			}
+			m.checkTableType(value, stmt)
+			if err := m.migrateTableOptions(stmt); err != nil {
+				return err
+			}
+			if err := m.migrateLoadUnits(stmt); err != nil {
+				return err
+			}
+			if err := m.migrateMasks(stmt); err != nil {
+				return err
+			}
+			if err := m.migrateFuzzySearchIndexes(stmt); err != nil {
+				return err
+			}
+			if err := m.migrateChecks(stmt); err != nil {
+				return err
+			}
+			if err := m.migrateForeignKeys(stmt); err != nil {
+				return err
+			}
+			return m.migrateGrants(stmt, tablePrivileges)
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, value := range virtualTables {
+		if err := m.migrateVirtualTable(value); err != nil {
+			return err
+		}
+		if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+			return m.migrateGrants(stmt, viewPrivileges)
+		}); err != nil {
+			return err
+		}
+	}
+
+	// views select from the tables, which exist by now
+	for _, value := range views {
+		view, _ := m.viewOf(value)
+		if err := m.migrateView(view); err != nil {
+			return err
+		}
+		if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+			return m.migrateGrants(stmt, viewPrivileges)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTableType warns about a table in another store than its model's or a global
+// temporary table of a regular model and vice versa, which AutoMigrate leaves to be
+// converted by hand, as that rewrites or recreates the table.
+func (m Migrator) checkTableType(value interface{}, stmt *gorm.Statement) {
+	store, hasStore := tableStoreOf(stmt)
+	temporary, hasTemporary := globalTemporaryOf(stmt)
+	if !hasStore && !hasTemporary {
+		return
+	}
+
+	tableType, err := m.TableType(value)
+	if err != nil {
+		return
+	}
+
+	current := tableType.Type()
+	switch {
+	case hasTemporary && temporary && current != "TEMPORARY":
+		m.DB.Logger.Warn(m.DB.Statement.Context, "hdb migrator: table %s is a %s table, its model a global temporary table", stmt.Table, current)
+	case hasTemporary && !temporary && current == "TEMPORARY":
+		m.DB.Logger.Warn(m.DB.Statement.Context, "hdb migrator: table %s is a global temporary table, its model a regular table", stmt.Table)
+	case hasStore && current != string(store) && (current == string(ColumnStore) || current == string(RowStore)):
+		m.DB.Logger.Warn(m.DB.Statement.Context, "hdb migrator: table %s is a %s table, its model a %s table", stmt.Table, current, store)
+	}
+}
@@ -0,0 +1,198 @@
+package hdb
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+type Diffed struct {
+	ID      uint    `gorm:"primaryKey"`
+	Code    string  `gorm:"primaryKey;size:20"`
+	Name    string  `gorm:"size:100;not null"`
+	Amount  float64 `gorm:"precision:10;scale:2"`
+	Rate    float64 `gorm:"type:numeric(8,2)"`
+	Count   int32   `gorm:"default:0"`
+	Flag    bool
+	Ratio   float32 `gorm:"type:float(20)"`
+	Comment string  `gorm:"size:10;comment:Remark"`
+}
+
+// columnType returns a column as read from SYS.TABLE_COLUMNS.
+func columnType(name, dataType string, length, scale int64, nullable bool, defaultValue, comment string) migrator.ColumnType {
+	column := migrator.ColumnType{
+		NameValue:         sql.NullString{String: name, Valid: true},
+		DataTypeValue:     sql.NullString{String: dataType, Valid: true},
+		NullableValue:     sql.NullBool{Bool: nullable, Valid: true},
+		DefaultValueValue: sql.NullString{String: defaultValue, Valid: defaultValue != ""},
+		CommentValue:      sql.NullString{String: comment, Valid: true},
+	}
+	if length > 0 {
+		column.LengthValue = sql.NullInt64{Int64: length, Valid: true}
+		column.DecimalSizeValue = sql.NullInt64{Int64: length, Valid: true}
+		column.ScaleValue = sql.NullInt64{Int64: scale, Valid: true}
+	}
+	return column
+}
+
+func TestDiffColumn(t *testing.T) {
+	diffed, err := schema.Parse(&Diffed{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse Diffed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		field  string
+		column migrator.ColumnType
+		diff   columnDiff
+	}{
+		{name: "same string", field: "Name", column: columnType("NAME", "NVARCHAR", 100, 0, false, "", "")},
+		{name: "same integer", field: "Count", column: columnType("COUNT", "INTEGER", 0, 0, true, "0", "")},
+		{name: "alias", field: "Rate", column: columnType("RATE", "DECIMAL", 8, 2, true, "", "")},
+		{name: "bool stored as TINYINT", field: "Flag", column: columnType("FLAG", "TINYINT", 0, 0, true, "", "")},
+		{name: "FLOAT(n) stored as REAL", field: "Ratio", column: columnType("RATIO", "REAL", 0, 0, true, "", "")},
+		{
+			name: "longer string", field: "Name", column: columnType("NAME", "NVARCHAR", 50, 0, false, "", ""),
+			diff: columnDiff{typeChanged: true},
+		},
+		{
+			name: "shorter string", field: "Name", column: columnType("NAME", "NVARCHAR", 200, 0, false, "", ""),
+			diff: columnDiff{typeChanged: true, narrowed: true},
+		},
+		{
+			name: "other type", field: "Name", column: columnType("NAME", "INTEGER", 0, 0, false, "", ""),
+			diff: columnDiff{typeChanged: true, narrowed: true},
+		},
+		{name: "same decimal", field: "Amount", column: columnType("AMOUNT", "DECIMAL", 10, 2, true, "", "")},
+		{
+			name: "wider decimal", field: "Amount", column: columnType("AMOUNT", "DECIMAL", 8, 2, true, "", ""),
+			diff: columnDiff{typeChanged: true},
+		},
+		{
+			name: "smaller scale", field: "Amount", column: columnType("AMOUNT", "DECIMAL", 10, 4, true, "", ""),
+			diff: columnDiff{typeChanged: true, narrowed: true},
+		},
+		{
+			name: "nullable", field: "Name", column: columnType("NAME", "NVARCHAR", 100, 0, true, "", ""),
+			diff: columnDiff{definitionChanged: true},
+		},
+		{
+			name: "default", field: "Count", column: columnType("COUNT", "INTEGER", 0, 0, true, "1", ""),
+			diff: columnDiff{definitionChanged: true},
+		},
+		{
+			name: "comment", field: "Comment", column: columnType("COMMENT", "NVARCHAR", 10, 0, true, "", "Note"),
+			diff: columnDiff{definitionChanged: true},
+		},
+		{name: "same comment", field: "Comment", column: columnType("COMMENT", "NVARCHAR", 10, 0, true, "", "Remark")},
+		{name: "same primary key", field: "Code", column: columnType("CODE", "NVARCHAR", 20, 0, false, "", "")},
+		{
+			name: "longer primary key", field: "Code", column: columnType("CODE", "NVARCHAR", 10, 0, false, "", ""),
+			diff: columnDiff{typeChanged: true, narrowed: true},
+		},
+		{
+			name: "other primary key type", field: "ID", column: columnType("ID", "INTEGER", 0, 0, false, "", ""),
+			diff: columnDiff{typeChanged: true, narrowed: true},
+		},
+	}
+
+	m := dryRunDB(t, Config{}).Migrator().(Migrator)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := m.diffColumn(diffed.LookUpField(tt.field), tt.column); diff != tt.diff {
+				t.Errorf("got %+v, want %+v", diff, tt.diff)
+			}
+		})
+	}
+}
+
+func TestParseDataType(t *testing.T) {
+	tests := []struct {
+		definition string
+		dataType   string
+		args       []int64
+	}{
+		{definition: "BIGINT", dataType: "BIGINT"},
+		{definition: "nvarchar(100)", dataType: "NVARCHAR", args: []int64{100}},
+		{definition: "DECIMAL(10, 2)", dataType: "DECIMAL", args: []int64{10, 2}},
+		{definition: "BIGINT GENERATED BY DEFAULT AS IDENTITY", dataType: "BIGINT"},
+		{definition: "FLOAT(24)", dataType: "FLOAT", args: []int64{24}},
+		{definition: "ST_GEOMETRY(4326)", dataType: "ST_GEOMETRY", args: []int64{4326}},
+		{definition: "NVARCHAR(n)", dataType: "NVARCHAR"},
+	}
+
+	for _, tt := range tests {
+		dataType, args := parseDataType(tt.definition)
+		if dataType != tt.dataType || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("got %s %v from %s, want %s %v", dataType, args, tt.definition, tt.dataType, tt.args)
+		}
+	}
+}
+
+type Keyed struct {
+	ID      [16]byte
+	Ref     [16]byte `gorm:"default:SYSUUID"`
+	Code    string   `gorm:"type:uuid;default:SYSUUID"`
+	Partner [16]byte
+}
+
+func TestUUIDDefault(t *testing.T) {
+	keyed, err := schema.Parse(&Keyed{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse Keyed: %v", err)
+	}
+
+	tests := []struct {
+		field      string
+		format     UUIDFormat
+		definition string
+	}{
+		{field: "ID", format: UUIDBinary, definition: "VARBINARY(16) DEFAULT SYSUUID"},
+		{field: "ID", definition: "NVARCHAR(36)"},
+		{field: "Ref", format: UUIDBinary, definition: "VARBINARY(16) DEFAULT SYSUUID"},
+		{field: "Ref", definition: "NVARCHAR(36)"},
+		{field: "Code", format: UUIDBinary, definition: "NVARCHAR(36)"},
+		{field: "Partner", format: UUIDBinary, definition: "VARBINARY(16)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			m := dryRunDB(t, Config{UUIDFormat: tt.format}).Migrator().(Migrator)
+			if definition := m.FullDataTypeOf(keyed.LookUpField(tt.field)).SQL; definition != tt.definition {
+				t.Errorf("got %s, want %s", definition, tt.definition)
+			}
+		})
+	}
+}
+
+func TestCurrentDatabase(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		schema string
+		want   string
+	}{
+		{name: "default schema", config: Config{DefaultSchema: "app"}, want: "APP"},
+		{name: "WithSchema", config: Config{DefaultSchema: "app"}, schema: "tenant_a", want: "TENANT_A"},
+		{name: "PreserveCase", config: Config{PreserveCase: true}, schema: "tenant_a", want: "tenant_a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.schema != "" {
+				ctx = WithSchema(ctx, tt.schema)
+			}
+			m := dryRunDB(t, tt.config).WithContext(ctx).Migrator().(Migrator)
+			if name := m.CurrentDatabase(); name != tt.want {
+				t.Errorf("got %s, want %s", name, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package hdb
+
+import (
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// MigrateColumnUnique adds the unique constraint of a field tagged with unique to its
+// column, and drops the unique constraint of a column whose field isn't. Columns a unique
+// index of the model already makes unique are left alone, as is a constraint HANA lists
+// for a unique index of the model, so AutoMigrate doesn't add a constraint on every run.
+func (m Migrator) MigrateColumnUnique(value interface{}, field *schema.Field, columnType gorm.ColumnType) error {
+	unique, ok := columnType.Unique()
+	if !ok || field.PrimaryKey || unique == field.Unique {
+		return nil
+	}
+
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if field.Unique {
+			if m.hasUniqueIndex(value, field.DBName) {
+				return nil
+			}
+			return m.DB.Migrator().CreateConstraint(value, m.DB.NamingStrategy.UniqueName(stmt.Table, field.DBName))
+		}
+
+		name, err := m.uniqueConstraint(stmt, field.DBName)
+		if err != nil || name == "" {
+			return err
+		}
+		if _, ok := stmt.Schema.ParseIndexes()[m.modelIndexName(stmt, name)]; ok {
+			return nil
+		}
+		return m.DB.Migrator().DropConstraint(value, name)
+	})
+}
+
+// uniqueConstraint returns the name of the unique constraint on the column alone from
+// SYS.CONSTRAINTS, or an empty name if there is none.
+func (m Migrator) uniqueConstraint(stmt *gorm.Statement, column string) (string, error) {
+	var name string
+	schemaName, table := m.resolveTable(stmt)
+	err := m.DB.Raw(
+		"SELECT CONSTRAINT_NAME FROM SYS.CONSTRAINTS WHERE SCHEMA_NAME = ? AND TABLE_NAME = ? AND IS_UNIQUE_KEY = 'TRUE' AND IS_PRIMARY_KEY = 'FALSE' "+
+			"GROUP BY CONSTRAINT_NAME HAVING COUNT(*) = 1 AND MIN(COLUMN_NAME) = ?",
+		schemaName, table, m.NormalizeIdentifier(column),
+	).Row().Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return name, err
+}
+
+// hasUniqueIndex checks whether a unique index other than the primary key's is on the
+// column of value alone.
+func (m Migrator) hasUniqueIndex(value interface{}, column string) bool {
+	indexes, err := m.DB.Migrator().GetIndexes(value)
+	if err != nil {
+		return false
+	}
+	for _, index := range indexes {
+		primaryKey, _ := index.PrimaryKey()
+		unique, _ := index.Unique()
+		if columns := index.Columns(); unique && !primaryKey && len(columns) == 1 && columns[0] == column {
+			return true
+		}
+	}
+	return false
+}
+
+// constraintNames returns the names of the primary key and unique constraints of the
+// statement's table from SYS.CONSTRAINTS, which HANA backs with indexes of the same name.
+func (m Migrator) constraintNames(stmt *gorm.Statement) (map[string]bool, error) {
+	schemaName, table := m.resolveTable(stmt)
+	rows, err := m.DB.Raw(
+		"SELECT DISTINCT CONSTRAINT_NAME FROM SYS.CONSTRAINTS WHERE SCHEMA_NAME = ? AND TABLE_NAME = ?", schemaName, table,
+	).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names[name] = true
+	}
+	return names, rows.Err()
+}
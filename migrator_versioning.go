@@ -0,0 +1,79 @@
+package hdb
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// systemVersioning returns the PERIOD FOR SYSTEM_TIME column of a system-versioned model's
+// table and its WITH SYSTEM VERSIONING clause after creating its history table, or empty
+// clauses for other models.
+func (m Migrator) systemVersioning(stmt *gorm.Statement) (period clause.Expr, versioning clause.Expr, err error) {
+	historyTable, ok := historyTableOf(stmt)
+	if !ok {
+		return period, versioning, nil
+	}
+
+	start, end := systemTimeFields(stmt.Schema)
+	if start == nil || end == nil {
+		return period, versioning, fmt.Errorf("system-versioned table %s needs fields tagged rowStart and rowEnd", stmt.Table)
+	}
+
+	if err := m.createHistoryTable(stmt, historyTable); err != nil {
+		return period, versioning, err
+	}
+
+	period = clause.Expr{SQL: "PERIOD FOR SYSTEM_TIME (?, ?)", Vars: []interface{}{clause.Column{Name: start.DBName}, clause.Column{Name: end.DBName}}}
+	versioning = clause.Expr{SQL: "WITH SYSTEM VERSIONING HISTORY TABLE ?", Vars: []interface{}{sequenceTable(stmt, historyTable)}}
+	return period, versioning, nil
+}
+
+// createHistoryTable creates the history table of a system-versioned model's table with the
+// same columns, but without identities, generated values and keys, as HANA requires.
+func (m Migrator) createHistoryTable(stmt *gorm.Statement, historyTable string) error {
+	var (
+		createTableSQL = "CREATE COLUMN TABLE ? ("
+		values         = []interface{}{sequenceTable(stmt, historyTable)}
+	)
+
+	for _, dbName := range stmt.Schema.DBNames {
+		field := stmt.Schema.FieldsByDBName[dbName]
+		if field.IgnoreMigration || isFlexibleField(field) {
+			continue
+		}
+
+		dataType := m.Dialector.DataTypeOf(field)
+		if field.AutoIncrement {
+			dataType = strings.TrimSpace(strings.TrimSuffix(dataType, identityOf(field)))
+		}
+		if field.NotNull || field.PrimaryKey || systemTimeOf(field) != "" {
+			dataType += " NOT NULL"
+		}
+
+		createTableSQL += "? ?,"
+		values = append(values, clause.Column{Name: dbName}, clause.Expr{SQL: dataType})
+	}
+
+	return m.DB.Exec(strings.TrimSuffix(createTableSQL, ",")+")", values...).Error
+}
+
+// dropSystemVersioning ends the system versioning of a system-versioned model's table, which
+// HANA drops only after.
+func (m Migrator) dropSystemVersioning(stmt *gorm.Statement) error {
+	if _, ok := historyTableOf(stmt); !ok {
+		return nil
+	}
+	return m.DB.Exec("ALTER TABLE ? DROP SYSTEM VERSIONING", m.CurrentTable(stmt)).Error
+}
+
+// dropHistoryTable drops the history table of a system-versioned model's table.
+func (m Migrator) dropHistoryTable(stmt *gorm.Statement) error {
+	historyTable, ok := historyTableOf(stmt)
+	if !ok || !m.HasTable(sequenceTable(stmt, historyTable).Name) {
+		return nil
+	}
+	return m.DB.Exec("DROP TABLE ?", sequenceTable(stmt, historyTable)).Error
+}
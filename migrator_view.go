@@ -0,0 +1,152 @@
+package hdb
+
+import (
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Viewer makes a model read from a view instead of a table, which AutoMigrate creates after
+// the tables of the other models and DropTable drops. View returns the view's query on db:
+//
+//	type OrderTotal struct {
+//		CustomerID uint
+//		Total      float64
+//	}
+//
+//	func (OrderTotal) View(db *gorm.DB) gorm.ViewOption {
+//		return gorm.ViewOption{
+//			Replace: true,
+//			Query:   db.Model(&Order{}).Select("CUSTOMER_ID, SUM(AMOUNT) AS TOTAL").Group("CUSTOMER_ID"),
+//		}
+//	}
+type Viewer interface {
+	View(db *gorm.DB) gorm.ViewOption
+}
+
+// ViewParameter is an input parameter of a view, which its query reads as :NAME.
+type ViewParameter struct {
+	Name string
+	// Type is the SQL type of the parameter, like DATE or NVARCHAR(10).
+	Type string
+	// Default is the value of the parameter when a query leaves it out, nil for none.
+	Default interface{}
+}
+
+// ParameterizedViewer declares the input parameters of the view of a Viewer model, whose
+// queries pass them with the ViewArguments scope.
+type ParameterizedViewer interface {
+	ViewParameters() []ViewParameter
+}
+
+// modelView is the view of a model implementing Viewer.
+type modelView struct {
+	name       string
+	option     gorm.ViewOption
+	parameters []ViewParameter
+}
+
+// viewOf returns the view of a model implementing Viewer.
+func (m Migrator) viewOf(value interface{}) (view modelView, ok bool) {
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if stmt.Schema == nil {
+			return nil
+		}
+		model := reflect.New(stmt.Schema.ModelType).Interface()
+		var viewer Viewer
+		if viewer, ok = model.(Viewer); ok {
+			view.name, view.option = m.fullTable(stmt), viewer.View(m.DB.Session(&gorm.Session{NewDB: true}))
+		}
+		if parameterized, isParameterized := model.(ParameterizedViewer); ok && isParameterized {
+			view.parameters = parameterized.ViewParameters()
+		}
+		return nil
+	})
+	return view, ok
+}
+
+// CreateView creates the view name selecting the rows of option's query, replacing an
+// existing view if option.Replace is set. HANA before 2.0 SPS 04 has no CREATE OR REPLACE,
+// replaced views are dropped and created again there. Views take no bind variables, the
+// query's variables are written as literals.
+func (m Migrator) CreateView(name string, option gorm.ViewOption) error {
+	return m.CreateParameterizedView(name, nil, option)
+}
+
+// CreateParameterizedView creates the view name like CreateView, with input parameters its
+// query reads as :NAME:
+//
+//	m.CreateParameterizedView("ORDERS_SINCE", []hdb.ViewParameter{{Name: "SINCE", Type: "DATE"}}, gorm.ViewOption{
+//		Query: db.Model(&Order{}).Where("CREATED_AT >= :SINCE"),
+//	})
+//	// CREATE VIEW "ORDERS_SINCE" (IN SINCE DATE) AS SELECT * FROM "ORDERS" WHERE CREATED_AT >= :SINCE
+func (m Migrator) CreateParameterizedView(name string, parameters []ViewParameter, option gorm.ViewOption) error {
+	if option.Query == nil {
+		return gorm.ErrSubQueryRequired
+	}
+
+	createViewSQL := "CREATE "
+	if option.Replace {
+		if m.DontSupportReplaceView {
+			if err := m.DropView(name); err != nil {
+				return err
+			}
+		} else {
+			createViewSQL += "OR REPLACE "
+		}
+	}
+
+	var (
+		sql  = new(strings.Builder)
+		stmt = &gorm.Statement{DB: m.DB}
+	)
+	sql.WriteString(createViewSQL + "VIEW ")
+	m.QuoteTo(sql, name)
+	if len(parameters) > 0 {
+		sql.WriteString(" (")
+		for idx, parameter := range parameters {
+			if idx > 0 {
+				sql.WriteString(", ")
+			}
+			sql.WriteString("IN " + parameter.Name + " " + parameter.Type)
+			if parameter.Default != nil {
+				sql.WriteString(" DEFAULT ")
+				stmt.AddVar(sql, parameter.Default)
+			}
+		}
+		sql.WriteByte(')')
+	}
+	sql.WriteString(" AS ")
+	stmt.AddVar(sql, option.Query)
+	if option.CheckOption != "" {
+		sql.WriteString(" " + option.CheckOption)
+	}
+	return m.DB.Exec(m.Explain(sql.String(), stmt.Vars...)).Error
+}
+
+// DropView drops the view name if it exists.
+func (m Migrator) DropView(name string) error {
+	if !m.HasView(name) {
+		return nil
+	}
+	return m.DB.Exec("DROP VIEW ?", clause.Table{Name: name}).Error
+}
+
+// HasView checks whether the view name exists in SYS.VIEWS.
+func (m Migrator) HasView(name string) bool {
+	var count int64
+	schemaName, view := m.CurrentSchema(m.DB.Statement, name)
+	m.DB.Raw("SELECT COUNT(*) FROM SYS.VIEWS WHERE SCHEMA_NAME = ? AND VIEW_NAME = ?", schemaName, view).Row().Scan(&count)
+	return count > 0
+}
+
+// migrateView creates the view of a model implementing Viewer unless it exists and is not
+// to be replaced.
+func (m Migrator) migrateView(view modelView) error {
+	if !view.option.Replace && m.HasView(view.name) {
+		return nil
+	}
+	return m.CreateParameterizedView(view.name, view.parameters, view.option)
+}
@@ -0,0 +1,142 @@
+package hdb
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RemoteTable is a table of a Smart Data Access remote source, which a virtual table reads
+// from. Its names are those of the remote system, taken as they are.
+type RemoteTable struct {
+	Source string
+	// Database is the database of the remote table, empty for remote sources without.
+	Database string
+	Schema   string
+	Table    string
+}
+
+// path returns the location of the remote table, like "SOURCE"."<NULL>"."SCHEMA"."TABLE".
+func (t RemoteTable) path() string {
+	database := t.Database
+	if database == "" {
+		database = "<NULL>"
+	}
+	return quoteIdentifier(t.Source) + "." + quoteIdentifier(database) + "." + quoteIdentifier(t.Schema) + "." + quoteIdentifier(t.Table)
+}
+
+// VirtualTabler makes a model's table a virtual table over a table of a remote source,
+// which AutoMigrate creates if missing and leaves alone otherwise, as its columns are
+// those of the remote table:
+//
+//	type Customer struct {
+//		ID   int
+//		Name string
+//	}
+//
+//	func (Customer) VirtualTable() hdb.RemoteTable {
+//		return hdb.RemoteTable{Source: "CRM", Schema: "SALES", Table: "CUSTOMERS"}
+//	}
+type VirtualTabler interface {
+	VirtualTable() RemoteTable
+}
+
+// virtualTableOf returns the remote table of the statement's model, if it implements
+// VirtualTabler.
+func virtualTableOf(stmt *gorm.Statement) (RemoteTable, bool) {
+	if stmt.Schema == nil {
+		return RemoteTable{}, false
+	}
+	if tabler, ok := reflect.New(stmt.Schema.ModelType).Interface().(VirtualTabler); ok {
+		return tabler.VirtualTable(), true
+	}
+	return RemoteTable{}, false
+}
+
+// isVirtualTable reports whether the model of value implements VirtualTabler.
+func (m Migrator) isVirtualTable(value interface{}) (ok bool) {
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		_, ok = virtualTableOf(stmt)
+		return nil
+	})
+	return ok
+}
+
+// CreateVirtualTable creates the table of value as virtual table over remote.
+func (m Migrator) CreateVirtualTable(value interface{}, remote RemoteTable) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.createVirtualTable(stmt, remote)
+	})
+}
+
+func (m Migrator) createVirtualTable(stmt *gorm.Statement, remote RemoteTable) error {
+	return m.DB.Exec("CREATE VIRTUAL TABLE ? AT ?", m.CurrentTable(stmt), clause.Expr{SQL: remote.path()}).Error
+}
+
+// DropVirtualTable drops the virtual table of value if it exists, leaving the remote table
+// alone.
+func (m Migrator) DropVirtualTable(value interface{}) error {
+	if !m.HasVirtualTable(value) {
+		return nil
+	}
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.DB.Exec("DROP TABLE ?", m.CurrentTable(stmt)).Error
+	})
+}
+
+// HasVirtualTable checks whether the table of value exists as virtual table in
+// SYS.VIRTUAL_TABLES.
+func (m Migrator) HasVirtualTable(value interface{}) bool {
+	_, err := m.VirtualTable(value)
+	return err == nil
+}
+
+// VirtualTable returns the remote table the virtual table of value reads from, from
+// SYS.VIRTUAL_TABLES.
+func (m Migrator) VirtualTable(value interface{}) (remote *RemoteTable, err error) {
+	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		remote, err = m.virtualTable(stmt)
+		return err
+	})
+	return remote, err
+}
+
+func (m Migrator) virtualTable(stmt *gorm.Statement) (*RemoteTable, error) {
+	var (
+		remote            RemoteTable
+		database          sql.NullString
+		schemaName, table = m.CurrentSchema(stmt, m.fullTable(stmt))
+	)
+	if err := m.DB.Raw(
+		"SELECT REMOTE_SOURCE_NAME, REMOTE_DB_NAME, REMOTE_OWNER_NAME, REMOTE_OBJECT_NAME FROM SYS.VIRTUAL_TABLES WHERE SCHEMA_NAME = ? AND TABLE_NAME = ?",
+		schemaName, table,
+	).Row().Scan(&remote.Source, &database, &remote.Schema, &remote.Table); err != nil {
+		return nil, err
+	}
+	if database.String != "<NULL>" {
+		remote.Database = database.String
+	}
+	return &remote, nil
+}
+
+// migrateVirtualTable creates the virtual table of a model implementing VirtualTabler if
+// missing, warning if it reads from another remote table than the model's.
+func (m Migrator) migrateVirtualTable(value interface{}) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		remote, _ := virtualTableOf(stmt)
+		current, err := m.virtualTable(stmt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return m.createVirtualTable(stmt, remote)
+		} else if err != nil {
+			return err
+		}
+
+		if *current != remote {
+			m.DB.Logger.Warn(m.DB.Statement.Context, "hdb migrator: virtual table %s reads from %s, its model from %s", stmt.Table, current.path(), remote.path())
+		}
+		return nil
+	})
+}
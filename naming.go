@@ -0,0 +1,77 @@
+package hdb
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm/schema"
+)
+
+// MaxIdentifierLength is the maximum length of HANA identifiers.
+const MaxIdentifierLength = 127
+
+// NamingStrategy produces upper case table, column, index and constraint names as HANA stores
+// unquoted identifiers, shortening names longer than MaxIdentifierLength with a hash suffix.
+//
+//	db, err := gorm.Open(hdb.Open(dsn), &gorm.Config{NamingStrategy: hdb.NamingStrategy{}})
+type NamingStrategy struct {
+	schema.NamingStrategy
+}
+
+var _ schema.Namer = NamingStrategy{}
+
+func (ns NamingStrategy) TableName(str string) string {
+	return ns.format(ns.NamingStrategy.TableName(str))
+}
+
+func (ns NamingStrategy) SchemaName(table string) string {
+	return ns.NamingStrategy.SchemaName(strings.ToLower(table))
+}
+
+func (ns NamingStrategy) ColumnName(table, column string) string {
+	return ns.format(ns.NamingStrategy.ColumnName(table, column))
+}
+
+func (ns NamingStrategy) JoinTableName(str string) string {
+	return ns.format(ns.NamingStrategy.JoinTableName(str))
+}
+
+func (ns NamingStrategy) RelationshipFKName(rel schema.Relationship) string {
+	return ns.format(ns.identifiers().RelationshipFKName(rel))
+}
+
+func (ns NamingStrategy) CheckerName(table, column string) string {
+	return ns.format(ns.identifiers().CheckerName(table, column))
+}
+
+func (ns NamingStrategy) IndexName(table, column string) string {
+	return ns.format(ns.identifiers().IndexName(table, column))
+}
+
+func (ns NamingStrategy) UniqueName(table, column string) string {
+	return ns.format(ns.identifiers().UniqueName(table, column))
+}
+
+// identifiers returns the embedded strategy limited to MaxIdentifierLength, so gorm's own
+// hash truncation of generated names applies HANA's limit.
+func (ns NamingStrategy) identifiers() schema.NamingStrategy {
+	strategy := ns.NamingStrategy
+	if strategy.IdentifierMaxLength == 0 || strategy.IdentifierMaxLength > MaxIdentifierLength {
+		strategy.IdentifierMaxLength = MaxIdentifierLength
+	}
+	return strategy
+}
+
+func (ns NamingStrategy) format(name string) string {
+	if maxLength := ns.identifiers().IdentifierMaxLength; utf8.RuneCountInString(name) > maxLength {
+		hash := sha1.Sum([]byte(name))
+		name = string([]rune(name)[:maxLength-8]) + hex.EncodeToString(hash[:])[:8]
+	}
+
+	if ns.NoLowerCase {
+		return name
+	}
+	return strings.ToUpper(name)
+}
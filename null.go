@@ -0,0 +1,54 @@
+package hdb
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// nullValue returns NULL for zero times with ZeroTimeAsNull and for empty strings with
+// EmptyStringAsNull, which are otherwise written as is.
+func (dialector Dialector) nullValue(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return nil, dialector.ZeroTimeAsNull && v.IsZero()
+	case *time.Time:
+		return nil, dialector.ZeroTimeAsNull && v != nil && v.IsZero()
+	case string:
+		return nil, dialector.EmptyStringAsNull && v == ""
+	case *string:
+		return nil, dialector.EmptyStringAsNull && v != nil && *v == ""
+	}
+	return nil, false
+}
+
+// insertNulls replaces the zero times and empty strings written as NULL in inserted values.
+func insertNulls(db *gorm.DB, values clause.Values) {
+	dialector, ok := db.Dialector.(*Dialector)
+	if !ok || !dialector.ZeroTimeAsNull && !dialector.EmptyStringAsNull {
+		return
+	}
+
+	for _, row := range values.Values {
+		for idx, value := range row {
+			if null, ok := dialector.nullValue(value); ok {
+				row[idx] = null
+			}
+		}
+	}
+}
+
+// updateNulls replaces the zero times and empty strings written as NULL in assignments.
+func updateNulls(db *gorm.DB, set clause.Set) {
+	dialector, ok := db.Dialector.(*Dialector)
+	if !ok || !dialector.ZeroTimeAsNull && !dialector.EmptyStringAsNull {
+		return
+	}
+
+	for idx, assignment := range set {
+		if null, ok := dialector.nullValue(assignment.Value); ok {
+			set[idx].Value = null
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package hdb
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestNullValue(t *testing.T) {
+	var (
+		zero, now   = time.Time{}, time.Now()
+		empty, name = "", "name"
+	)
+
+	tests := []struct {
+		name   string
+		config Config
+		value  interface{}
+		null   bool
+	}{
+		{name: "zero time", config: Config{ZeroTimeAsNull: true}, value: zero, null: true},
+		{name: "zero time pointer", config: Config{ZeroTimeAsNull: true}, value: &zero, null: true},
+		{name: "nil time pointer", config: Config{ZeroTimeAsNull: true}, value: (*time.Time)(nil)},
+		{name: "time", config: Config{ZeroTimeAsNull: true}, value: now},
+		{name: "zero time kept", value: zero},
+		{name: "empty string", config: Config{EmptyStringAsNull: true}, value: empty, null: true},
+		{name: "empty string pointer", config: Config{EmptyStringAsNull: true}, value: &empty, null: true},
+		{name: "string", config: Config{EmptyStringAsNull: true}, value: name},
+		{name: "string pointer", config: Config{EmptyStringAsNull: true}, value: &name},
+		{name: "empty string kept", config: Config{ZeroTimeAsNull: true}, value: empty},
+		{name: "number", config: Config{ZeroTimeAsNull: true, EmptyStringAsNull: true}, value: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			if _, null := (Dialector{Config: &config}).nullValue(tt.value); null != tt.null {
+				t.Errorf("got NULL %v, want %v", null, tt.null)
+			}
+		})
+	}
+}
+
+func TestInsertAndUpdateNulls(t *testing.T) {
+	db := &gorm.DB{Config: &gorm.Config{Dialector: &Dialector{Config: &Config{ZeroTimeAsNull: true}}}}
+
+	values := clause.Values{Values: [][]interface{}{{time.Time{}, "", 1}}}
+	insertNulls(db, values)
+	if row := values.Values[0]; row[0] != nil || row[1] != "" || row[2] != 1 {
+		t.Errorf("got %v, want the zero time inserted as NULL only", row)
+	}
+
+	set := clause.Set{{Column: clause.Column{Name: "deleted_at"}, Value: time.Time{}}, {Column: clause.Column{Name: "name"}, Value: ""}}
+	updateNulls(db, set)
+	if set[0].Value != nil || set[1].Value != "" {
+		t.Errorf("got %v, want the zero time updated to NULL only", set)
+	}
+}
@@ -0,0 +1,64 @@
+package hdb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrReservedWord identifier is a HANA reserved word
+var ErrReservedWord = errors.New("identifier is a reserved word")
+
+// reservedWords are the reserved words of the SAP HANA SQL reference, which cannot be used
+// as unquoted identifiers.
+var reservedWords = map[string]struct{}{}
+
+func init() {
+	for _, word := range strings.Fields(`
+		ALL ALTER AS BEFORE BEGIN BOTH CASE CHAR CONDITION CONNECT CROSS CUBE
+		CURRENT_CONNECTION CURRENT_DATE CURRENT_SCHEMA CURRENT_TIME CURRENT_TIMESTAMP
+		CURRENT_TRANSACTION_ISOLATION_LEVEL CURRENT_USER CURRENT_UTCDATE CURRENT_UTCTIME
+		CURRENT_UTCTIMESTAMP CURRVAL CURSOR DECLARE DEFERRED DISTINCT ELSE ELSEIF END EXCEPT
+		EXCEPTION EXEC FALSE FOR FROM FULL GROUP HAVING IF IN INNER INOUT INTERSECT INTO IS
+		JOIN LATERAL LEADING LEFT LIMIT LOOP MINUS NATURAL NCHAR NEXTVAL NULL ON ORDER OUT
+		PRIOR RETURN RETURNS REVERSE RIGHT ROLLUP ROWID SELECT SESSION_USER SET SQL START
+		SYSUUID TABLESAMPLE TOP TRAILING TRUE UNION UNKNOWN USING UTCTIMESTAMP VALUES WHEN
+		WHERE WHILE WITH`) {
+		reservedWords[word] = struct{}{}
+	}
+}
+
+// IsReservedWord reports whether name is a HANA reserved word.
+func IsReservedWord(name string) bool {
+	_, ok := reservedWords[strings.ToUpper(name)]
+	return ok
+}
+
+// validateIdentifiers returns an ErrReservedWord error naming the table or column of the
+// statement's model that is a reserved word. Such names are quoted in generated SQL, so
+// they are only rejected with RejectReservedWords, e.g. to keep hand written SQL working.
+func (dialector Dialector) validateIdentifiers(stmt *gorm.Statement) error {
+	if !dialector.RejectReservedWords || stmt.Schema == nil {
+		return nil
+	}
+
+	if IsReservedWord(stmt.Table) {
+		return fmt.Errorf("%w: table %q", ErrReservedWord, stmt.Table)
+	}
+
+	for _, dbName := range stmt.Schema.DBNames {
+		if err := dialector.validateIdentifier(stmt, dbName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (dialector Dialector) validateIdentifier(stmt *gorm.Statement, column string) error {
+	if dialector.RejectReservedWords && IsReservedWord(column) {
+		return fmt.Errorf("%w: column %q of table %q", ErrReservedWord, column, stmt.Table)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package hdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	hdbdriver "github.com/SAP/go-hdb/driver"
+)
+
+// RetryPolicy controls how sessions lost to a node restart or takeover are re-established.
+// Connecting is retried MaxRetries times, waiting Backoff (doubled per attempt) in between.
+// Retryable overrides the detection of lost sessions.
+type RetryPolicy struct {
+	MaxRetries int
+	Backoff    time.Duration
+	Retryable  func(err error) bool
+}
+
+// sessionLostCodes are the client side error codes of sessions lost to the server.
+var sessionLostCodes = map[int]bool{
+	-10108: true, // session has been reconnected
+	-10709: true, // connection failed
+	-10807: true, // connection lost
+}
+
+func isSessionLost(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var hdbErr hdbdriver.Error
+	return errors.As(err, &hdbErr) && sessionLostCodes[hdbErr.Code()]
+}
+
+func (policy *RetryPolicy) retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if policy != nil && policy.Retryable != nil {
+		return policy.Retryable(err)
+	}
+	return isSessionLost(err)
+}
+
+func (policy *RetryPolicy) connect(ctx context.Context, connector driver.Connector) (conn driver.Conn, err error) {
+	var backoff time.Duration
+	if policy != nil {
+		backoff = policy.Backoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		conn, err = connector.Connect(ctx)
+		if err == nil || policy == nil || attempt >= policy.MaxRetries || !policy.retryable(err) {
+			return conn, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
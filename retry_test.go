@@ -0,0 +1,152 @@
+package hdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+)
+
+// hdbError is a HANA error of a code.
+type hdbError struct {
+	code int
+}
+
+func (e hdbError) Error() string   { return fmt.Sprintf("SQL Error %d", e.code) }
+func (e hdbError) NumError() int   { return 1 }
+func (e hdbError) SetIdx(idx int)  {}
+func (e hdbError) StmtNo() int     { return 0 }
+func (e hdbError) Code() int       { return e.code }
+func (e hdbError) Position() int   { return 0 }
+func (e hdbError) Level() int      { return 1 }
+func (e hdbError) Text() string    { return e.Error() }
+func (e hdbError) IsWarning() bool { return false }
+func (e hdbError) IsError() bool   { return true }
+func (e hdbError) IsFatal() bool   { return false }
+
+func TestIsSessionLost(t *testing.T) {
+	tests := []struct {
+		err  error
+		lost bool
+	}{
+		{err: driver.ErrBadConn, lost: true},
+		{err: io.EOF, lost: true},
+		{err: fmt.Errorf("read: %w", io.ErrUnexpectedEOF), lost: true},
+		{err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, lost: true},
+		{err: hdbError{code: -10108}, lost: true},
+		{err: hdbError{code: -10709}, lost: true},
+		{err: fmt.Errorf("exec: %w", hdbError{code: -10807}), lost: true},
+		{err: hdbError{code: 259}},
+		{err: hdbError{code: 301}},
+		{err: errors.New("invalid argument")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			if lost := isSessionLost(tt.err); lost != tt.lost {
+				t.Errorf("got %v, want %v", lost, tt.lost)
+			}
+		})
+	}
+}
+
+// flakyConnector fails to connect with the errors of errs before connecting.
+type flakyConnector struct {
+	errs     []error
+	attempts int
+}
+
+func (c *flakyConnector) Connect(context.Context) (driver.Conn, error) {
+	c.attempts++
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		return nil, err
+	}
+	return &recordingConn{}, nil
+}
+
+func (c *flakyConnector) Driver() driver.Driver { return nil }
+
+func TestRetryPolicyConnect(t *testing.T) {
+	lost := hdbError{code: -10709}
+
+	connector := &flakyConnector{errs: []error{lost, lost}}
+	if _, err := (&RetryPolicy{MaxRetries: 2}).connect(context.Background(), connector); err != nil {
+		t.Errorf("got %v, want to connect on the third attempt", err)
+	}
+
+	connector = &flakyConnector{errs: []error{lost, lost, lost}}
+	if _, err := (&RetryPolicy{MaxRetries: 2}).connect(context.Background(), connector); err != lost || connector.attempts != 3 {
+		t.Errorf("got %v after %d attempts, want %v after 3", err, connector.attempts, lost)
+	}
+
+	authentication := hdbError{code: 10}
+	connector = &flakyConnector{errs: []error{authentication}}
+	if _, err := (&RetryPolicy{MaxRetries: 2}).connect(context.Background(), connector); err != authentication || connector.attempts != 1 {
+		t.Errorf("got %v after %d attempts, want %v at once", err, connector.attempts, authentication)
+	}
+
+	connector = &flakyConnector{errs: []error{lost}}
+	if _, err := (*RetryPolicy)(nil).connect(context.Background(), connector); err != lost {
+		t.Errorf("got %v without policy, want %v", err, lost)
+	}
+}
+
+// lostConn is a connection whose session was lost.
+type lostConn struct {
+	recordingConn
+}
+
+func (c *lostConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return nil, hdbError{code: -10807}
+}
+
+func (c *lostConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return nil, hdbError{code: -10807}
+}
+
+func TestSessionConnLost(t *testing.T) {
+	conn := &sessionConn{Conn: &lostConn{}, variables: map[string]string{}}
+
+	// statements changing data are not retried, their error is kept
+	if _, err := conn.ExecContext(context.Background(), "DELETE FROM T", nil); !errors.Is(err, hdbError{code: -10807}) {
+		t.Errorf("got %v, want the error of the lost session", err)
+	}
+	if conn.IsValid() || conn.ResetSession(context.Background()) != driver.ErrBadConn {
+		t.Error("got the lost connection kept in the pool")
+	}
+
+	conn = &sessionConn{Conn: &lostConn{}, variables: map[string]string{}}
+	if _, err := conn.QueryContext(context.Background(), "SELECT 1 FROM DUMMY", nil); err != driver.ErrBadConn {
+		t.Errorf("got %v, want driver.ErrBadConn to retry the query", err)
+	}
+}
+
+// lostStmt is a prepared statement whose session was lost.
+type lostStmt struct{}
+
+func (lostStmt) Close() error                               { return nil }
+func (lostStmt) NumInput() int                              { return -1 }
+func (lostStmt) Exec([]driver.Value) (driver.Result, error) { return nil, hdbError{code: -10807} }
+func (lostStmt) Query([]driver.Value) (driver.Rows, error)  { return nil, hdbError{code: -10807} }
+
+func TestSessionStmtLost(t *testing.T) {
+	conn := &sessionConn{Conn: &recordingConn{}, variables: map[string]string{}}
+	stmt := &sessionStmt{Stmt: lostStmt{}, conn: conn}
+
+	if _, err := stmt.ExecContext(context.Background(), nil); !errors.Is(err, hdbError{code: -10807}) {
+		t.Errorf("got %v, want the error of the lost session", err)
+	}
+	if conn.IsValid() {
+		t.Error("got the lost connection kept in the pool")
+	}
+
+	stmt.conn = &sessionConn{Conn: &recordingConn{}, variables: map[string]string{}}
+	if _, err := stmt.QueryContext(context.Background(), nil); err != driver.ErrBadConn {
+		t.Errorf("got %v, want driver.ErrBadConn to retry the query", err)
+	}
+}
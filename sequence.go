@@ -0,0 +1,75 @@
+package hdb
+
+import (
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// sequenceOf returns the sequence backing a field tagged with sequence:NAME.
+func sequenceOf(field *schema.Field) string {
+	return field.TagSettings["SEQUENCE"]
+}
+
+// sequenceTable returns the sequence name, qualified with the schema of schema qualified
+// tables as sequences are created next to their table.
+func sequenceTable(stmt *gorm.Statement, name string) clause.Table {
+	if stmt.Schema != nil && !strings.Contains(name, ".") {
+		if tables := strings.Split(stmt.Schema.Table, "."); len(tables) == 2 {
+			return clause.Table{Name: strings.Trim(tables[0], `"`) + "." + name}
+		}
+	}
+	return clause.Table{Name: name}
+}
+
+// createSequenceValues draws the values of blank sequence backed fields from their
+// sequence's NEXTVAL before inserting, so that created records carry their keys.
+func createSequenceValues(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	var rows []reflect.Value
+	switch rv := db.Statement.ReflectValue; rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			rows = append(rows, reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		rows = append(rows, rv)
+	}
+
+	for _, field := range db.Statement.Schema.Fields {
+		sequence := sequenceOf(field)
+		if sequence == "" {
+			continue
+		}
+
+		var blank []reflect.Value
+		for _, row := range rows {
+			if _, isZero := field.ValueOf(db.Statement.Context, row); isZero {
+				blank = append(blank, row)
+			}
+		}
+		if len(blank) == 0 {
+			continue
+		}
+
+		var values []int64
+		if err := db.Session(&gorm.Session{NewDB: true}).Raw(
+			"SELECT ?.NEXTVAL FROM SERIES_GENERATE_INTEGER(1, 0, ?)", sequenceTable(db.Statement, sequence), len(blank),
+		).Scan(&values).Error; err != nil {
+			db.AddError(err)
+			return
+		}
+
+		for i, row := range blank {
+			if i < len(values) {
+				db.AddError(field.Set(db.Statement.Context, row, values[i]))
+			}
+		}
+	}
+}
@@ -0,0 +1,547 @@
+package hdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	hdbdriver "github.com/SAP/go-hdb/driver"
+)
+
+type sessionVariablesKey struct{}
+
+// WithSessionVariable returns a context that sets the HANA session variable key (e.g.
+// APPLICATIONUSER) to value on the connection executing statements with that context.
+func WithSessionVariable(ctx context.Context, key, value string) context.Context {
+	variables := map[string]string{key: value}
+	if parent, ok := ctx.Value(sessionVariablesKey{}).(map[string]string); ok {
+		for k, v := range parent {
+			if k != key {
+				variables[k] = v
+			}
+		}
+	}
+	return context.WithValue(ctx, sessionVariablesKey{}, variables)
+}
+
+type schemaKey struct{}
+
+// WithSchema returns a context that resolves unqualified table names of statements executed
+// with that context, and the migrator's catalog lookups, against schema, which is normalized
+// like identifiers. Table names are not qualified: the pooled connection running a statement
+// is switched to the schema with SET SCHEMA before the statement, and back to the default
+// schema before a statement without it, each taking an extra round trip.
+func WithSchema(ctx context.Context, schema string) context.Context {
+	return context.WithValue(ctx, schemaKey{}, schema)
+}
+
+func schemaFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	schema, _ := ctx.Value(schemaKey{}).(string)
+	return schema
+}
+
+// sessionConnector runs session setup statements on every new physical connection and
+// hands out connections applying context scoped session settings.
+type sessionConnector struct {
+	driver.Connector
+	statements        []string
+	hook              func(ctx context.Context, conn driver.Conn) error
+	variables         map[string]string
+	readOnly          bool
+	retry             *RetryPolicy
+	schema            string
+	preserveCase      bool
+	uuidFormat        UUIDFormat
+	location          *time.Location
+	checkUintOverflow bool
+}
+
+func (c *sessionConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.retry.connect(ctx, c.Connector)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, statement := range c.statements {
+		if err = execConn(ctx, conn, statement); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	if c.hook != nil {
+		if err = c.hook(ctx, conn); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return &sessionConn{Conn: conn, defaults: c.variables, variables: map[string]string{}, readOnly: c.readOnly, retry: c.retry, defaultSchema: c.schema, preserveCase: c.preserveCase, uuidFormat: c.uuidFormat, location: c.location, checkUintOverflow: c.checkUintOverflow}, nil
+}
+
+func execConn(ctx context.Context, conn driver.Conn, query string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		if _, err := execer.ExecContext(ctx, query, nil); err != driver.ErrSkip {
+			return err
+		}
+	}
+
+	stmt, err := conn.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(nil)
+	return err
+}
+
+func (dialector Dialector) sessionStatements() (statements []string) {
+	if dialector.ReadOnly {
+		statements = append(statements, "SET TRANSACTION READ ONLY")
+	}
+
+	keys := make([]string, 0, len(dialector.SessionVariables))
+	for key := range dialector.SessionVariables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		statements = append(statements, setSessionVariableSQL(key, dialector.SessionVariables[key]))
+	}
+	return append(statements, dialector.OnConnect...)
+}
+
+func setSessionVariableSQL(key, value string) string {
+	return "SET " + quoteString(key) + " = " + quoteString(value)
+}
+
+func quoteString(str string) string {
+	return "'" + strings.ReplaceAll(str, "'", "''") + "'"
+}
+
+func quoteIdentifier(str string) string {
+	return `"` + strings.ReplaceAll(str, `"`, `""`) + `"`
+}
+
+func queryConn(ctx context.Context, conn driver.Conn, query string) (value string, err error) {
+	queryer, ok := conn.(driver.QueryerContext)
+	if !ok {
+		return "", driver.ErrSkip
+	}
+
+	rows, err := queryer.QueryContext(ctx, query, nil)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	dest := make([]driver.Value, len(rows.Columns()))
+	if err = rows.Next(dest); err == io.EOF {
+		return "", sql.ErrNoRows
+	} else if err != nil {
+		return "", err
+	}
+	if len(dest) > 0 {
+		value, _ = dest[0].(string)
+	}
+	return value, nil
+}
+
+// sessionConn wraps a driver connection to apply the session variables of the context
+// before a statement is prepared or executed.
+type sessionConn struct {
+	driver.Conn
+	defaults   map[string]string
+	variables  map[string]string
+	readOnly   bool
+	retry      *RetryPolicy
+	lost       bool
+	uuidFormat UUIDFormat
+	location   *time.Location
+
+	checkUintOverflow bool
+
+	// schema is the schema switched to by WithSchema, defaultSchema the one to switch back to
+	schema        string
+	defaultSchema string
+	preserveCase  bool
+}
+
+var (
+	_ driver.ConnBeginTx        = (*sessionConn)(nil)
+	_ driver.ConnPrepareContext = (*sessionConn)(nil)
+	_ driver.ExecerContext      = (*sessionConn)(nil)
+	_ driver.QueryerContext     = (*sessionConn)(nil)
+	_ driver.NamedValueChecker  = (*sessionConn)(nil)
+	_ driver.Pinger             = (*sessionConn)(nil)
+	_ driver.SessionResetter    = (*sessionConn)(nil)
+	_ driver.Validator          = (*sessionConn)(nil)
+	_ hdbdriver.Conn            = (*sessionConn)(nil)
+)
+
+// checkErr marks the connection as lost on session errors so the pool discards it. Errors
+// of idempotent operations are reported as driver.ErrBadConn to let database/sql retry
+// them on a new connection.
+func (c *sessionConn) checkErr(err error, idempotent bool) error {
+	if !c.retry.retryable(err) {
+		return err
+	}
+
+	c.lost = true
+	if idempotent {
+		return driver.ErrBadConn
+	}
+	return err
+}
+
+func (c *sessionConn) apply(ctx context.Context) error {
+	variables, _ := ctx.Value(sessionVariablesKey{}).(map[string]string)
+
+	for key := range c.variables {
+		if _, ok := variables[key]; !ok {
+			query := "UNSET " + quoteString(key)
+			if value, ok := c.defaults[key]; ok {
+				query = setSessionVariableSQL(key, value)
+			}
+
+			if err := execConn(ctx, c.Conn, query); err != nil {
+				return err
+			}
+			delete(c.variables, key)
+		}
+	}
+
+	for key, value := range variables {
+		if current, ok := c.variables[key]; !ok || current != value {
+			if err := execConn(ctx, c.Conn, setSessionVariableSQL(key, value)); err != nil {
+				return err
+			}
+			c.variables[key] = value
+		}
+	}
+
+	return c.applySchema(ctx)
+}
+
+func (c *sessionConn) applySchema(ctx context.Context) (err error) {
+	schema := schemaFromContext(ctx)
+	if !c.preserveCase {
+		// the migrator looks schemas up as Dialector.NormalizeIdentifier returns them
+		schema = strings.ToUpper(schema)
+	}
+	if schema == c.schema {
+		return nil
+	}
+
+	if c.defaultSchema == "" {
+		if c.defaultSchema, err = queryConn(ctx, c.Conn, "SELECT CURRENT_SCHEMA FROM DUMMY"); err != nil {
+			return err
+		}
+	}
+
+	target := schema
+	if target == "" {
+		target = c.defaultSchema
+	}
+	if err = execConn(ctx, c.Conn, "SET SCHEMA "+quoteIdentifier(target)); err != nil {
+		return err
+	}
+	c.schema = schema
+	return nil
+}
+
+// BeginTx starts read-only transactions for read-only sessions. go-hdb sets the access mode
+// for the session rather than the transaction, so it is switched back to read write once
+// a read-only transaction of a read write session ends.
+func (c *sessionConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := c.apply(ctx); err != nil {
+		return nil, c.checkErr(err, true)
+	}
+
+	if c.readOnly {
+		opts.ReadOnly = true
+	}
+
+	var (
+		tx  driver.Tx
+		err error
+	)
+	if beginTx, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = beginTx.BeginTx(ctx, opts)
+	} else if opts.ReadOnly || opts.Isolation != driver.IsolationLevel(0) {
+		return nil, errors.New("hdb: transaction options not supported by driver")
+	} else {
+		tx, err = c.Conn.Begin()
+	}
+
+	if err != nil {
+		return nil, c.checkErr(err, true)
+	}
+
+	if opts.ReadOnly && !c.readOnly {
+		tx = &readOnlyTx{Tx: tx, conn: c.Conn}
+	}
+	return tx, nil
+}
+
+type readOnlyTx struct {
+	driver.Tx
+	conn driver.Conn
+}
+
+func (tx *readOnlyTx) Commit() error {
+	if err := tx.Tx.Commit(); err != nil {
+		return err
+	}
+	return execConn(context.Background(), tx.conn, "SET TRANSACTION READ WRITE")
+}
+
+func (tx *readOnlyTx) Rollback() error {
+	if err := tx.Tx.Rollback(); err != nil {
+		return err
+	}
+	return execConn(context.Background(), tx.conn, "SET TRANSACTION READ WRITE")
+}
+
+func (c *sessionConn) PrepareContext(ctx context.Context, query string) (stmt driver.Stmt, err error) {
+	if err = c.apply(ctx); err != nil {
+		return nil, c.checkErr(err, true)
+	}
+
+	if prepare, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = prepare.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+
+	if err != nil {
+		return nil, c.checkErr(err, true)
+	}
+
+	return &sessionStmt{Stmt: stmt, conn: c}, nil
+}
+
+func (c *sessionConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	if err := c.apply(ctx); err != nil {
+		return nil, c.checkErr(err, true)
+	}
+
+	result, err := execer.ExecContext(ctx, query, args)
+	if err != nil {
+		return nil, c.checkErr(err, false)
+	}
+	return result, nil
+}
+
+func (c *sessionConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	if err := c.apply(ctx); err != nil {
+		return nil, c.checkErr(err, true)
+	}
+
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != nil {
+		return nil, c.checkErr(err, true)
+	}
+
+	if c.location != nil {
+		rows = &locationRows{Rows: rows, location: c.location}
+	}
+	return rows, nil
+}
+
+func (c *sessionConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if err := c.convertNamedValue(nv); err != nil {
+		return err
+	}
+
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// convertNamedValue converts the values go-hdb doesn't bind as is.
+func (c *sessionConn) convertNamedValue(nv *driver.NamedValue) error {
+	if rows, ok := nv.Value.([][]interface{}); ok {
+		// the rows of a bulk statement
+		for _, row := range rows {
+			for i := range row {
+				value := driver.NamedValue{Value: row[i]}
+				if err := c.convertNamedValue(&value); err != nil {
+					return err
+				}
+				row[i] = value.Value
+			}
+		}
+		return nil
+	}
+
+	if c.checkUintOverflow {
+		if err := checkUintOverflow(nv.Value); err != nil {
+			return err
+		}
+	}
+
+	if value, ok, err := customValue(nv.Value); ok {
+		if err != nil {
+			return err
+		}
+		nv.Value = value
+	} else if value, ok := decimalValue(nv.Value); ok {
+		nv.Value = value
+	} else if value, ok := uuidValue(nv.Value, c.uuidFormat); ok {
+		nv.Value = value
+	} else if c.location != nil {
+		if value, ok := utcValue(nv.Value); ok {
+			nv.Value = value
+		}
+	}
+	return nil
+}
+
+// sessionStmt applies the conversions of its connection to its arguments, as database/sql
+// prefers the NamedValueChecker of statements over the connection's, reads the times of
+// its results in the connection's location and checks its errors like the connection.
+type sessionStmt struct {
+	driver.Stmt
+	conn *sessionConn
+}
+
+var (
+	_ driver.StmtExecContext  = (*sessionStmt)(nil)
+	_ driver.StmtQueryContext = (*sessionStmt)(nil)
+)
+
+func (s *sessionStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (result driver.Result, err error) {
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		result, err = execer.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValues(args); err != nil {
+			return nil, err
+		}
+		result, err = s.Stmt.Exec(values)
+	}
+
+	if err != nil {
+		return nil, s.conn.checkErr(err, false)
+	}
+	return result, nil
+}
+
+func (s *sessionStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (rows driver.Rows, err error) {
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+
+	if err != nil {
+		return nil, s.conn.checkErr(err, true)
+	}
+	if s.conn.location == nil {
+		return rows, nil
+	}
+	return &locationRows{Rows: rows, location: s.conn.location}, nil
+}
+
+func (s *sessionStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if err := s.conn.convertNamedValue(nv); err != nil {
+		return err
+	}
+
+	if checker, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// checkUintOverflow rejects unsigned values exceeding BIGINT, the widest HANA integer type.
+func checkUintOverflow(value interface{}) error {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		if rv.Uint() > math.MaxInt64 {
+			return fmt.Errorf("%w: %d", ErrUintOverflow, rv.Uint())
+		}
+	}
+	return nil
+}
+
+func (c *sessionConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return c.checkErr(pinger.Ping(ctx), true)
+	}
+	return nil
+}
+
+func (c *sessionConn) ResetSession(ctx context.Context) error {
+	if c.lost {
+		return driver.ErrBadConn
+	}
+
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *sessionConn) IsValid() bool {
+	if c.lost {
+		return false
+	}
+
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *sessionConn) HDBVersion() *hdbdriver.Version {
+	if conn, ok := c.Conn.(hdbdriver.Conn); ok {
+		return conn.HDBVersion()
+	}
+	return nil
+}
+
+func (c *sessionConn) DatabaseName() string {
+	if conn, ok := c.Conn.(hdbdriver.Conn); ok {
+		return conn.DatabaseName()
+	}
+	return ""
+}
+
+func (c *sessionConn) DBConnectInfo(ctx context.Context, databaseName string) (*hdbdriver.DBConnectInfo, error) {
+	if conn, ok := c.Conn.(hdbdriver.Conn); ok {
+		return conn.DBConnectInfo(ctx, databaseName)
+	}
+	return nil, driver.ErrSkip
+}
@@ -0,0 +1,428 @@
+package hdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// WKB is a geometry in well-known binary representation, stored in ST_GEOMETRY columns or,
+// with a type tag, in ST_POINT columns. The spatial reference system is set with the srid
+// tag.
+//
+//	Location hdb.WKB `gorm:"type:st_point;srid:4326"`
+type WKB []byte
+
+// GormDataType implements the schema.GormDataTypeInterface interface.
+func (WKB) GormDataType() string {
+	return "ST_GEOMETRY"
+}
+
+// Value passes the geometry hex encoded, as go-hdb expects for spatial parameters.
+func (g WKB) Value() (driver.Value, error) {
+	if g == nil {
+		return nil, nil
+	}
+	return hex.EncodeToString(g), nil
+}
+
+// Scan decodes the hex encoded geometries go-hdb returns for spatial columns.
+func (g *WKB) Scan(src interface{}) (err error) {
+	switch v := src.(type) {
+	case nil:
+		*g = nil
+	case string:
+		*g, err = hex.DecodeString(v)
+	case []byte:
+		*g, err = hex.DecodeString(string(v))
+	default:
+		err = fmt.Errorf("hdb: cannot scan %T into WKB", src)
+	}
+	return err
+}
+
+// Point is a point geometry, stored in ST_POINT columns.
+//
+//	Location hdb.Point `gorm:"srid:4326"`
+type Point struct {
+	X, Y float64
+	SRID int
+}
+
+// Polygon is a polygon geometry of an exterior ring followed by optional interior rings,
+// stored in ST_GEOMETRY columns.
+type Polygon struct {
+	Rings [][][2]float64
+	SRID  int
+}
+
+// Geometry is a geometry of any type in well-known binary representation.
+type Geometry struct {
+	WKB  WKB
+	SRID int
+}
+
+// GormDataType implements the schema.GormDataTypeInterface interface.
+func (Point) GormDataType() string {
+	return "ST_POINT"
+}
+
+// GormDataType implements the schema.GormDataTypeInterface interface.
+func (Polygon) GormDataType() string {
+	return "ST_GEOMETRY"
+}
+
+// GormDataType implements the schema.GormDataTypeInterface interface.
+func (Geometry) GormDataType() string {
+	return "ST_GEOMETRY"
+}
+
+// GormValue writes the point with ST_GeomFromWKB.
+func (p Point) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	return geomFromWKB(p.wkb(), p.SRID)
+}
+
+// GormValue writes the polygon with ST_GeomFromWKB.
+func (p Polygon) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	return geomFromWKB(p.wkb(), p.SRID)
+}
+
+// GormValue writes the geometry with ST_GeomFromWKB.
+func (g Geometry) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	if g.WKB == nil {
+		return clause.Expr{SQL: "NULL"}
+	}
+	return geomFromWKB(g.WKB, g.SRID)
+}
+
+func geomFromWKB(wkb []byte, srid int) clause.Expr {
+	if srid != 0 {
+		return clause.Expr{SQL: "ST_GeomFromWKB(?, ?)", Vars: []interface{}{binaryValue(wkb), srid}}
+	}
+	return clause.Expr{SQL: "ST_GeomFromWKB(?)", Vars: []interface{}{binaryValue(wkb)}}
+}
+
+// binaryValue binds a []byte as a single VARBINARY parameter, which clause.Expr would
+// expand to a list of bytes.
+type binaryValue []byte
+
+func (b binaryValue) Value() (driver.Value, error) {
+	return []byte(b), nil
+}
+
+// Scan reads the point from a spatial column, ST_AsWKB, ST_AsEWKB or ST_AsGeoJSON.
+func (p *Point) Scan(src interface{}) error {
+	g, err := scanGeometry(src)
+	if err != nil || g == nil {
+		return err
+	}
+	if g.kind != wkbPoint || len(g.rings) != 1 || len(g.rings[0]) != 1 {
+		return fmt.Errorf("hdb: cannot scan %s into Point", g.kind)
+	}
+	*p = Point{X: g.rings[0][0][0], Y: g.rings[0][0][1], SRID: g.srid}
+	return nil
+}
+
+// Scan reads the polygon from a spatial column, ST_AsWKB, ST_AsEWKB or ST_AsGeoJSON.
+func (p *Polygon) Scan(src interface{}) error {
+	g, err := scanGeometry(src)
+	if err != nil || g == nil {
+		return err
+	}
+	if g.kind != wkbPolygon {
+		return fmt.Errorf("hdb: cannot scan %s into Polygon", g.kind)
+	}
+	*p = Polygon{Rings: g.rings, SRID: g.srid}
+	return nil
+}
+
+// Scan reads the geometry from a spatial column, ST_AsWKB or ST_AsEWKB.
+func (g *Geometry) Scan(src interface{}) error {
+	var wkb WKB
+	if err := wkb.Scan(src); err != nil {
+		return err
+	}
+
+	*g = Geometry{WKB: wkb}
+	if len(wkb) >= 9 {
+		if order := byteOrder(wkb[0]); order.Uint32(wkb[1:])&ewkbSRID != 0 {
+			g.SRID = int(order.Uint32(wkb[5:]))
+		}
+	}
+	return nil
+}
+
+type wkbType uint32
+
+const (
+	wkbPoint   wkbType = 1
+	wkbPolygon wkbType = 3
+
+	// ewkbSRID flags extended WKB types followed by the SRID
+	ewkbSRID = 0x20000000
+)
+
+func (t wkbType) String() string {
+	switch t {
+	case wkbPoint:
+		return "Point"
+	case wkbPolygon:
+		return "Polygon"
+	}
+	return fmt.Sprintf("geometry type %d", uint32(t))
+}
+
+func (p Point) wkb() []byte {
+	return geometry{kind: wkbPoint, rings: [][][2]float64{{{p.X, p.Y}}}}.wkb()
+}
+
+func (p Polygon) wkb() []byte {
+	return geometry{kind: wkbPolygon, rings: p.Rings}.wkb()
+}
+
+// geometry is a decoded point or polygon, a point being a single ring of one position.
+type geometry struct {
+	kind  wkbType
+	srid  int
+	rings [][][2]float64
+}
+
+func (g geometry) wkb() []byte {
+	b := appendUint32([]byte{1}, uint32(g.kind))
+	if g.kind == wkbPolygon {
+		b = appendUint32(b, uint32(len(g.rings)))
+	}
+
+	for _, ring := range g.rings {
+		if g.kind == wkbPolygon {
+			b = appendUint32(b, uint32(len(ring)))
+		}
+		for _, position := range ring {
+			b = appendUint64(b, math.Float64bits(position[0]))
+			b = appendUint64(b, math.Float64bits(position[1]))
+		}
+	}
+	return b
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func scanGeometry(src interface{}) (*geometry, error) {
+	var text string
+	switch v := src.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return nil, fmt.Errorf("hdb: cannot scan %T into geometry", src)
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(text), "{") {
+		return decodeGeoJSON([]byte(text))
+	}
+
+	var wkb WKB
+	if err := wkb.Scan(text); err != nil {
+		return nil, err
+	}
+	return decodeWKB(wkb)
+}
+
+func byteOrder(b byte) binary.ByteOrder {
+	if b == 0 {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
+func decodeWKB(b []byte) (*geometry, error) {
+	errInvalid := errors.New("hdb: invalid well-known binary geometry")
+	if len(b) < 5 {
+		return nil, errInvalid
+	}
+
+	var (
+		order = byteOrder(b[0])
+		kind  = order.Uint32(b[1:])
+		g     = &geometry{kind: wkbType(kind &^ ewkbSRID)}
+	)
+	b = b[5:]
+
+	readUint32 := func() (uint32, bool) {
+		if len(b) < 4 {
+			return 0, false
+		}
+		v := order.Uint32(b)
+		b = b[4:]
+		return v, true
+	}
+	readPositions := func(n uint32) ([][2]float64, bool) {
+		if uint64(len(b)) < uint64(n)*16 {
+			return nil, false
+		}
+		positions := make([][2]float64, n)
+		for i := range positions {
+			positions[i] = [2]float64{math.Float64frombits(order.Uint64(b)), math.Float64frombits(order.Uint64(b[8:]))}
+			b = b[16:]
+		}
+		return positions, true
+	}
+
+	if kind&ewkbSRID != 0 {
+		srid, ok := readUint32()
+		if !ok {
+			return nil, errInvalid
+		}
+		g.srid = int(srid)
+	}
+
+	switch g.kind {
+	case wkbPoint:
+		position, ok := readPositions(1)
+		if !ok {
+			return nil, errInvalid
+		}
+		g.rings = [][][2]float64{position}
+	case wkbPolygon:
+		count, ok := readUint32()
+		for i := uint32(0); ok && i < count; i++ {
+			var n uint32
+			if n, ok = readUint32(); ok {
+				var ring [][2]float64
+				if ring, ok = readPositions(n); ok {
+					g.rings = append(g.rings, ring)
+				}
+			}
+		}
+		if !ok {
+			return nil, errInvalid
+		}
+	}
+	return g, nil
+}
+
+func decodeGeoJSON(b []byte) (*geometry, error) {
+	var object struct {
+		Type        string
+		Coordinates json.RawMessage
+	}
+	if err := json.Unmarshal(b, &object); err != nil {
+		return nil, err
+	}
+
+	g := &geometry{}
+	switch object.Type {
+	case "Point":
+		var position [2]float64
+		if err := json.Unmarshal(object.Coordinates, &position); err != nil {
+			return nil, err
+		}
+		g.kind, g.rings = wkbPoint, [][][2]float64{{position}}
+	case "Polygon":
+		g.kind = wkbPolygon
+		if err := json.Unmarshal(object.Coordinates, &g.rings); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("hdb: unsupported GeoJSON type %q", object.Type)
+	}
+	return g, nil
+}
+
+func (g geometry) geoJSON() ([]byte, error) {
+	object := struct {
+		Type        string      `json:"type"`
+		Coordinates interface{} `json:"coordinates"`
+	}{Type: g.kind.String(), Coordinates: g.rings}
+
+	switch g.kind {
+	case wkbPoint:
+		object.Coordinates = g.rings[0][0]
+	case wkbPolygon:
+	default:
+		return nil, fmt.Errorf("hdb: unsupported %s", g.kind)
+	}
+	return json.Marshal(object)
+}
+
+func init() {
+	schema.RegisterSerializer("geojson", GeoJSONSerializer{})
+}
+
+// GeoJSONSerializer stores GeoJSON points and polygons held by string or []byte fields in
+// spatial columns. It is registered as geojson.
+//
+//	Area string `gorm:"serializer:geojson;type:st_geometry;srid:4326"`
+type GeoJSONSerializer struct{}
+
+func (GeoJSONSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var text []byte
+	if dbValue != nil {
+		g, err := scanGeometry(dbValue)
+		if err != nil {
+			return err
+		}
+		if text, err = g.geoJSON(); err != nil {
+			return err
+		}
+	}
+
+	fieldValue := reflect.New(field.FieldType).Elem()
+	switch fieldValue.Kind() {
+	case reflect.String:
+		fieldValue.SetString(string(text))
+	case reflect.Slice:
+		fieldValue.SetBytes(text)
+	default:
+		return fmt.Errorf("invalid field type %s for GeoJSONSerializer", field.FieldType)
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue)
+	return nil
+}
+
+func (GeoJSONSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	var text []byte
+	switch v := fieldValue.(type) {
+	case string:
+		text = []byte(v)
+	case []byte:
+		text = v
+	default:
+		return nil, fmt.Errorf("invalid field type %T for GeoJSONSerializer", fieldValue)
+	}
+
+	if len(text) == 0 {
+		return nil, nil
+	}
+
+	g, err := decodeGeoJSON(text)
+	if err != nil {
+		return nil, err
+	}
+	return WKB(g.wkb()).Value()
+}
@@ -0,0 +1,105 @@
+package hdb
+
+import (
+	"context"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPointScan(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   interface{}
+		point Point
+	}{
+		{name: "WKB", src: "0101000000000000000000F03F0000000000000040", point: Point{X: 1, Y: 2}},
+		{name: "WKB bytes", src: []byte("0101000000000000000000F03F0000000000000040"), point: Point{X: 1, Y: 2}},
+		{name: "big endian EWKB", src: "0020000001000010E63FF00000000000004000000000000000", point: Point{X: 1, Y: 2, SRID: 4326}},
+		{name: "GeoJSON", src: `{"type": "Point", "coordinates": [1.5, -2]}`, point: Point{X: 1.5, Y: -2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var point Point
+			if err := point.Scan(tt.src); err != nil {
+				t.Fatalf("failed to scan %v: %v", tt.src, err)
+			}
+			if point != tt.point {
+				t.Errorf("got %+v, want %+v", point, tt.point)
+			}
+		})
+	}
+}
+
+func TestPointScanErrors(t *testing.T) {
+	for _, src := range []interface{}{
+		"01010000",   // truncated
+		"0101000000", // point without position
+		"nothex",
+		`{"type": "LineString", "coordinates": [[1, 2], [3, 4]]}`,
+		hex.EncodeToString(Polygon{Rings: [][][2]float64{{{0, 0}, {1, 0}, {0, 1}, {0, 0}}}}.wkb()),
+		42,
+	} {
+		var point Point
+		if err := point.Scan(src); err == nil {
+			t.Errorf("got %+v from %v, want an error", point, src)
+		}
+	}
+}
+
+func TestPolygonWKB(t *testing.T) {
+	polygon := Polygon{Rings: [][][2]float64{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{2, 2}, {4, 2}, {4, 4}, {2, 2}},
+	}}
+
+	var scanned Polygon
+	if err := scanned.Scan(hex.EncodeToString(polygon.wkb())); err != nil {
+		t.Fatalf("failed to scan polygon: %v", err)
+	}
+	if !reflect.DeepEqual(scanned, polygon) {
+		t.Errorf("got %+v, want %+v", scanned, polygon)
+	}
+}
+
+func TestGeometryScan(t *testing.T) {
+	var g Geometry
+	if err := g.Scan("0101000020E6100000000000000000F03F0000000000000040"); err != nil {
+		t.Fatalf("failed to scan geometry: %v", err)
+	}
+	if g.SRID != 4326 || len(g.WKB) != 25 {
+		t.Errorf("got SRID %d and %d bytes, want SRID 4326 and 25 bytes", g.SRID, len(g.WKB))
+	}
+
+	if err := g.Scan(nil); err != nil || g.WKB != nil {
+		t.Errorf("got %+v %v from NULL, want an empty geometry", g, err)
+	}
+}
+
+func TestGeoJSONSerializer(t *testing.T) {
+	value, err := GeoJSONSerializer{}.Value(context.Background(), nil, reflect.Value{}, `{"type":"Point","coordinates":[1,2]}`)
+	if err != nil {
+		t.Fatalf("failed to convert GeoJSON: %v", err)
+	}
+	if wkb, _ := value.(string); !strings.EqualFold(wkb, "0101000000000000000000F03F0000000000000040") {
+		t.Errorf("got %v, want the hex encoded WKB of POINT(1 2)", value)
+	}
+
+	if value, err = (GeoJSONSerializer{}).Value(context.Background(), nil, reflect.Value{}, ""); value != nil || err != nil {
+		t.Errorf("got %v %v for an empty string, want NULL", value, err)
+	}
+
+	g, err := scanGeometry("0103000000010000000400000000000000000000000000000000000000000000000000F03F00000000000000000000000000000000000000000000F03F00000000000000000000000000000000")
+	if err != nil {
+		t.Fatalf("failed to scan polygon: %v", err)
+	}
+	text, err := g.geoJSON()
+	if err != nil {
+		t.Fatalf("failed to convert polygon: %v", err)
+	}
+	if want := `{"type":"Polygon","coordinates":[[[0,0],[1,0],[0,1],[0,0]]]}`; string(text) != want {
+		t.Errorf("got %s, want %s", text, want)
+	}
+}
@@ -0,0 +1,155 @@
+package hdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrNoSession local temporary table used outside a transaction or connection
+var ErrNoSession = errors.New("local temporary tables need a transaction or connection")
+
+// LocalTemporaryTable is a local temporary table of a model, visible to the session that
+// created it only and dropped when it ends. As gorm runs statements on any connection of
+// its pool, local temporary tables are used within a transaction or db.Connection.
+type LocalTemporaryTable struct {
+	// Name is the name of the table, starting with #.
+	Name  string
+	db    *gorm.DB
+	model interface{}
+}
+
+// CreateLocalTemporaryTable creates a local temporary column table named name, prefixed
+// with # unless it is, with the columns of model on the session of db. Pushing a large set
+// of keys to the server and joining them is faster than binding them in an IN list:
+//
+//	err := db.Transaction(func(tx *gorm.DB) error {
+//		keys, err := hdb.CreateLocalTemporaryTable(tx, "keys", &Key{})
+//		if err != nil {
+//			return err
+//		}
+//		if err := keys.Insert(ids); err != nil {
+//			return err
+//		}
+//		return tx.Joins("JOIN ? K ON K.ID = ORDERS.ID", keys.Table()).Find(&orders).Error
+//	})
+func CreateLocalTemporaryTable(db *gorm.DB, name string, model interface{}) (*LocalTemporaryTable, error) {
+	if _, ok := db.Statement.ConnPool.(*sql.DB); ok {
+		return nil, ErrNoSession
+	}
+	if !strings.HasPrefix(name, "#") {
+		name = "#" + name
+	}
+
+	table := &LocalTemporaryTable{Name: name, db: db, model: model}
+	m, ok := db.Table(name).Migrator().(Migrator)
+	if !ok {
+		return nil, fmt.Errorf("local temporary table %s needs the hdb dialector", name)
+	}
+	err := m.RunWithValue(model, func(stmt *gorm.Statement) error {
+		return m.createTable(stmt, "LOCAL TEMPORARY COLUMN")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// Table returns the table to use in queries of the session, like in joins.
+func (t *LocalTemporaryTable) Table() clause.Table {
+	return clause.Table{Name: t.Name}
+}
+
+// Insert inserts rows, a slice of the table's model, as one batch bound with all rows.
+// Auto increment fields blank in all rows are left to the database.
+func (t *LocalTemporaryTable) Insert(rows interface{}) error {
+	stmt := &gorm.Statement{DB: t.db}
+	if err := stmt.Parse(t.model); err != nil {
+		return err
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(rows))
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return fmt.Errorf("rows of local temporary table %s are no slice: %T", t.Name, rows)
+	}
+	if rv.Len() == 0 {
+		return nil
+	}
+
+	var (
+		ctx     = t.db.Statement.Context
+		fields  []*schema.Field
+		columns []string
+	)
+	for _, dbName := range stmt.Schema.DBNames {
+		field := stmt.Schema.FieldsByDBName[dbName]
+		if field.IgnoreMigration || !field.Creatable || isGeneratedField(field) || isFlexibleField(field) {
+			continue
+		}
+		if field.AutoIncrement && blankInAll(ctx, field, rv) {
+			continue
+		}
+		fields = append(fields, field)
+		columns = append(columns, stmt.Quote(dbName))
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("local temporary table %s has no columns to insert", t.Name)
+	}
+
+	values := make([][]interface{}, rv.Len())
+	for i := range values {
+		row := reflect.Indirect(rv.Index(i))
+		values[i] = make([]interface{}, len(fields))
+		for j, field := range fields {
+			values[i][j], _ = field.ValueOf(ctx, row)
+		}
+	}
+
+	query := "INSERT INTO " + stmt.Quote(t.Name) + " (" + strings.Join(columns, ",") +
+		") VALUES (" + strings.TrimSuffix(strings.Repeat("?,", len(fields)), ",") + ")"
+	return t.exec(query, values)
+}
+
+// blankInAll reports whether field is zero in all rows.
+func blankInAll(ctx context.Context, field *schema.Field, rows reflect.Value) bool {
+	for i := 0; i < rows.Len(); i++ {
+		if _, isZero := field.ValueOf(ctx, reflect.Indirect(rows.Index(i))); !isZero {
+			return false
+		}
+	}
+	return true
+}
+
+// exec runs a bulk statement with rows of values, which go-hdb sends in batches of its bulk
+// size, bypassing gorm that would expand them.
+func (t *LocalTemporaryTable) exec(query string, rows [][]interface{}) error {
+	ctx := t.db.Statement.Context
+	begin := time.Now()
+
+	stmt, err := t.db.Statement.ConnPool.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	var rowsAffected int64
+	result, err := stmt.ExecContext(ctx, rows)
+	if err == nil {
+		rowsAffected, _ = result.RowsAffected()
+	}
+	t.db.Logger.Trace(ctx, begin, func() (string, int64) { return query, rowsAffected }, err)
+	return err
+}
+
+// Drop drops the table before the session ends.
+func (t *LocalTemporaryTable) Drop() error {
+	return t.db.Exec("DROP TABLE ?", t.Table()).Error
+}
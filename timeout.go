@@ -0,0 +1,77 @@
+package hdb
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+const queryTimeoutCancelKey = "hdb:query_timeout_cancel"
+
+// registerQueryTimeout bounds every statement by QueryTimeout unless the statement's
+// context already carries a deadline. go-hdb drops the session of a cancelled statement,
+// which aborts it on the server.
+func (dialector Dialector) registerQueryTimeout(db *gorm.DB) error {
+	callback := db.Callback()
+
+	if err := callback.Create().Before("gorm:begin_transaction").Register("hdb:begin_query_timeout", dialector.beginQueryTimeout); err != nil {
+		return err
+	}
+	if err := callback.Create().After("gorm:commit_or_rollback_transaction").Register("hdb:end_query_timeout", endQueryTimeout); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:begin_transaction").Register("hdb:begin_query_timeout", dialector.beginQueryTimeout); err != nil {
+		return err
+	}
+	if err := callback.Update().After("gorm:commit_or_rollback_transaction").Register("hdb:end_query_timeout", endQueryTimeout); err != nil {
+		return err
+	}
+	if err := callback.Delete().Before("gorm:begin_transaction").Register("hdb:begin_query_timeout", dialector.beginQueryTimeout); err != nil {
+		return err
+	}
+	if err := callback.Delete().After("gorm:commit_or_rollback_transaction").Register("hdb:end_query_timeout", endQueryTimeout); err != nil {
+		return err
+	}
+	if err := callback.Query().Before("gorm:query").Register("hdb:begin_query_timeout", dialector.beginQueryTimeout); err != nil {
+		return err
+	}
+	if err := callback.Query().After("gorm:after_query").Register("hdb:end_query_timeout", endQueryTimeout); err != nil {
+		return err
+	}
+	if err := callback.Row().Before("gorm:row").Register("hdb:begin_query_timeout", dialector.beginQueryTimeout); err != nil {
+		return err
+	}
+	if err := callback.Row().After("gorm:row").Register("hdb:end_query_timeout", endRowQueryTimeout); err != nil {
+		return err
+	}
+	if err := callback.Raw().Before("gorm:raw").Register("hdb:begin_query_timeout", dialector.beginQueryTimeout); err != nil {
+		return err
+	}
+	return callback.Raw().After("gorm:raw").Register("hdb:end_query_timeout", endQueryTimeout)
+}
+
+func (dialector Dialector) beginQueryTimeout(db *gorm.DB) {
+	if _, ok := db.Statement.Context.Deadline(); ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(db.Statement.Context, dialector.QueryTimeout)
+	db.Statement.Context = ctx
+	db.Statement.Settings.Store(queryTimeoutCancelKey, cancel)
+}
+
+func endQueryTimeout(db *gorm.DB) {
+	if cancel, ok := db.Statement.Settings.LoadAndDelete(queryTimeoutCancelKey); ok {
+		cancel.(context.CancelFunc)()
+	}
+}
+
+// endRowQueryTimeout keeps the timeout of Row and Rows, whose rows are read after the
+// callback and closed by a cancelled context, until it expires, unless the query failed.
+func endRowQueryTimeout(db *gorm.DB) {
+	if db.Error != nil {
+		endQueryTimeout(db)
+		return
+	}
+	db.Statement.Settings.Delete(queryTimeoutCancelKey)
+}
@@ -0,0 +1,80 @@
+package hdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestQueryTimeout(t *testing.T) {
+	dialector := Dialector{Config: &Config{QueryTimeout: time.Minute}}
+	db := &gorm.DB{Statement: &gorm.Statement{Context: context.Background()}}
+
+	dialector.beginQueryTimeout(db)
+	ctx := db.Statement.Context
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > time.Minute {
+		t.Fatalf("got deadline %v, want one within a minute", deadline)
+	}
+
+	endQueryTimeout(db)
+	if ctx.Err() != context.Canceled {
+		t.Errorf("got %v after the statement, want the context cancelled", ctx.Err())
+	}
+	if _, ok := db.Statement.Settings.Load(queryTimeoutCancelKey); ok {
+		t.Error("got the cancel function kept after the statement")
+	}
+}
+
+func TestQueryTimeoutKeepsDeadline(t *testing.T) {
+	dialector := Dialector{Config: &Config{QueryTimeout: time.Minute}}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	db := &gorm.DB{Statement: &gorm.Statement{Context: ctx}}
+
+	dialector.beginQueryTimeout(db)
+	if db.Statement.Context != ctx {
+		t.Error("got the context of the statement replaced, want its deadline kept")
+	}
+
+	endQueryTimeout(db)
+	if ctx.Err() != nil {
+		t.Errorf("got %v, want the context of the caller left alone", ctx.Err())
+	}
+}
+
+func TestRowQueryTimeout(t *testing.T) {
+	dialector := Dialector{Config: &Config{QueryTimeout: 10 * time.Millisecond}}
+	db := &gorm.DB{Statement: &gorm.Statement{Context: context.Background()}}
+
+	dialector.beginQueryTimeout(db)
+	ctx := db.Statement.Context
+	endRowQueryTimeout(db)
+	if ctx.Err() != nil {
+		t.Fatalf("got %v after Row, want the context alive to read the rows", ctx.Err())
+	}
+	if _, ok := db.Statement.Settings.Load(queryTimeoutCancelKey); ok {
+		t.Error("got the cancel function kept after Row")
+	}
+
+	// the timer of the context is released when the timeout expires
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("got the context alive after its timeout")
+	}
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Errorf("got %v, want the deadline exceeded", ctx.Err())
+	}
+
+	db = &gorm.DB{Statement: &gorm.Statement{Context: context.Background()}}
+	dialector.beginQueryTimeout(db)
+	ctx = db.Statement.Context
+	db.Error = errNoServer
+	endRowQueryTimeout(db)
+	if ctx.Err() != context.Canceled {
+		t.Errorf("got %v after a failed Row, want the context cancelled", ctx.Err())
+	}
+}
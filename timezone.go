@@ -0,0 +1,117 @@
+package hdb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"time"
+)
+
+// utcValue converts time values to UTC, as HANA datetime columns have no time zone.
+func utcValue(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC(), true
+	case *time.Time:
+		if v == nil {
+			return nil, true
+		}
+		return v.UTC(), true
+	case sql.NullTime:
+		if !v.Valid {
+			return nil, true
+		}
+		return v.Time.UTC(), true
+	}
+	return nil, false
+}
+
+func namedValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, driver.ErrSkip
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// locationRows converts the times read to a location.
+type locationRows struct {
+	driver.Rows
+	location *time.Location
+}
+
+var (
+	_ driver.RowsNextResultSet              = (*locationRows)(nil)
+	_ driver.RowsColumnTypeScanType         = (*locationRows)(nil)
+	_ driver.RowsColumnTypeDatabaseTypeName = (*locationRows)(nil)
+	_ driver.RowsColumnTypeLength           = (*locationRows)(nil)
+	_ driver.RowsColumnTypeNullable         = (*locationRows)(nil)
+	_ driver.RowsColumnTypePrecisionScale   = (*locationRows)(nil)
+)
+
+func (r *locationRows) Next(dest []driver.Value) error {
+	if err := r.Rows.Next(dest); err != nil {
+		return err
+	}
+
+	for i, value := range dest {
+		if t, ok := value.(time.Time); ok {
+			// the stored time is UTC, whatever location the driver reports
+			dest[i] = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC).In(r.location)
+		}
+	}
+	return nil
+}
+
+func (r *locationRows) HasNextResultSet() bool {
+	if rows, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return rows.HasNextResultSet()
+	}
+	return false
+}
+
+func (r *locationRows) NextResultSet() error {
+	if rows, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return rows.NextResultSet()
+	}
+	return io.EOF
+}
+
+func (r *locationRows) ColumnTypeScanType(index int) reflect.Type {
+	if rows, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return rows.ColumnTypeScanType(index)
+	}
+	return reflect.TypeOf(new(interface{})).Elem()
+}
+
+func (r *locationRows) ColumnTypeDatabaseTypeName(index int) string {
+	if rows, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return rows.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func (r *locationRows) ColumnTypeLength(index int) (int64, bool) {
+	if rows, ok := r.Rows.(driver.RowsColumnTypeLength); ok {
+		return rows.ColumnTypeLength(index)
+	}
+	return 0, false
+}
+
+func (r *locationRows) ColumnTypeNullable(index int) (bool, bool) {
+	if rows, ok := r.Rows.(driver.RowsColumnTypeNullable); ok {
+		return rows.ColumnTypeNullable(index)
+	}
+	return false, false
+}
+
+func (r *locationRows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	if rows, ok := r.Rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return rows.ColumnTypePrecisionScale(index)
+	}
+	return 0, 0, false
+}
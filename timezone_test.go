@@ -0,0 +1,98 @@
+package hdb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+	"time"
+)
+
+var cet = time.FixedZone("CET", 60*60)
+
+func TestUTCValue(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 30, 0, 0, cet)
+	utc := time.Date(2024, 3, 1, 11, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  interface{}
+		ok    bool
+	}{
+		{name: "time", value: at, want: utc, ok: true},
+		{name: "pointer", value: &at, want: utc, ok: true},
+		{name: "nil pointer", value: (*time.Time)(nil), ok: true},
+		{name: "null time", value: sql.NullTime{Time: at, Valid: true}, want: utc, ok: true},
+		{name: "invalid null time", value: sql.NullTime{}, ok: true},
+		{name: "string", value: "2024-03-01 12:30:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, ok := utcValue(tt.value)
+			if ok != tt.ok {
+				t.Fatalf("got converted %v, want %v", ok, tt.ok)
+			}
+			if want, isTime := tt.want.(time.Time); isTime {
+				if got, _ := value.(time.Time); !got.Equal(want) || got.Location() != time.UTC {
+					t.Errorf("got %v, want %v", value, want)
+				}
+			} else if value != tt.want {
+				t.Errorf("got %v, want %v", value, tt.want)
+			}
+		})
+	}
+}
+
+// valueRows returns a single row of values.
+type valueRows struct {
+	values []driver.Value
+	read   bool
+}
+
+func (r *valueRows) Columns() []string { return make([]string, len(r.values)) }
+func (r *valueRows) Close() error      { return nil }
+
+func (r *valueRows) Next(dest []driver.Value) error {
+	if r.read {
+		return io.EOF
+	}
+	r.read = true
+	copy(dest, r.values)
+	return nil
+}
+
+func TestLocationRows(t *testing.T) {
+	// go-hdb reports the UTC times stored in the local location of the client
+	stored := time.Date(2024, 3, 1, 11, 30, 0, 0, time.Local)
+	rows := &locationRows{Rows: &valueRows{values: []driver.Value{stored, "name"}}, location: cet}
+
+	dest := make([]driver.Value, 2)
+	if err := rows.Next(dest); err != nil {
+		t.Fatalf("failed to read row: %v", err)
+	}
+	at, _ := dest[0].(time.Time)
+	if want := time.Date(2024, 3, 1, 12, 30, 0, 0, cet); !at.Equal(want) || at.Location() != cet {
+		t.Errorf("got %v, want %v", dest[0], want)
+	}
+	if dest[1] != "name" {
+		t.Errorf("got %v, want the other values as they are", dest[1])
+	}
+
+	if err := rows.Next(dest); err != io.EOF {
+		t.Errorf("got %v after the last row, want io.EOF", err)
+	}
+}
+
+func TestSessionConnTimeLocation(t *testing.T) {
+	conn := &sessionConn{Conn: &recordingConn{}, location: cet}
+
+	nv := driver.NamedValue{Value: time.Date(2024, 3, 1, 12, 30, 0, 0, cet)}
+	if err := conn.CheckNamedValue(&nv); err != driver.ErrSkip {
+		t.Fatalf("got %v, want driver.ErrSkip to convert the value by default", err)
+	}
+	if at, _ := nv.Value.(time.Time); at.Location() != time.UTC || at.Hour() != 11 {
+		t.Errorf("got %v, want the time in UTC", nv.Value)
+	}
+}
@@ -17,11 +17,12 @@ func Update(db *gorm.DB) {
 		if db.Statement.SQL.String() == "" {
 			db.Statement.SQL.Grow(180)
 			db.Statement.AddClauseIfNotExists(clause.Update{})
-			if set := callbacks.ConvertToAssignments(db.Statement); len(set) != 0 {
-				db.Statement.AddClause(set)
-			} else {
+			set := callbacks.ConvertToAssignments(db.Statement)
+			updateNulls(db, set)
+			if set = updateFlexibleColumns(db.Statement, set); len(set) == 0 {
 				return
 			}
+			db.Statement.AddClause(set)
 			db.Statement.Build("UPDATE", "SET", "WHERE", "ORDER BY", "LIMIT")
 		}
 
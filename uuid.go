@@ -0,0 +1,137 @@
+package hdb
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// UUIDFormat selects how UUID fields are stored.
+type UUIDFormat int
+
+const (
+	// UUIDString stores UUIDs readable as NVARCHAR(36).
+	UUIDString UUIDFormat = iota
+	// UUIDBinary stores UUIDs as VARBINARY(16), like SYSUUID returns them.
+	UUIDBinary
+)
+
+// isUUIDField reports whether the field holds a UUID, which are 16 byte arrays like
+// github.com/google/uuid's UUID and fields tagged with type:uuid.
+func isUUIDField(field *schema.Field) bool {
+	if strings.EqualFold(string(field.DataType), "uuid") {
+		return true
+	}
+	return isUUIDType(field.IndirectFieldType)
+}
+
+func isUUIDType(t reflect.Type) bool {
+	return t.Kind() == reflect.Array && t.Len() == 16 && t.Elem().Kind() == reflect.Uint8
+}
+
+// uuidTypeOf returns the type of a UUID field, string fields always store the string form.
+func (dialector Dialector) uuidTypeOf(field *schema.Field) string {
+	if dialector.UUIDFormat == UUIDBinary && field.IndirectFieldType.Kind() != reflect.String {
+		return "VARBINARY(16)"
+	}
+	return "NVARCHAR(36)"
+}
+
+// hasUUIDDefault reports whether a UUID field defaults to a generated UUID, which UUID
+// primary keys without a default value do.
+func hasUUIDDefault(field *schema.Field) bool {
+	if !isUUIDField(field) {
+		return false
+	}
+	if field.HasDefaultValue {
+		return strings.EqualFold(field.DefaultValue, "SYSUUID")
+	}
+	return field.PrimaryKey
+}
+
+// uuidDefaultOf returns the SYSUUID default of a UUID field defaulting to a generated UUID.
+// SYSUUID returns VARBINARY(16), HANA takes no expression formatting it as the default of
+// NVARCHAR(36) columns, which get no default and are filled in by Create only.
+func (dialector Dialector) uuidDefaultOf(field *schema.Field) (string, bool) {
+	if !hasUUIDDefault(field) || dialector.uuidTypeOf(field) != "VARBINARY(16)" {
+		return "", false
+	}
+	return "SYSUUID", true
+}
+
+// uuidValue converts 16 byte arrays to the stored UUID representation, as the values of
+// UUID types are strings.
+func uuidValue(value interface{}, format UUIDFormat) (interface{}, bool) {
+	rv := reflect.ValueOf(value)
+	if value == nil || !isUUIDType(rv.Type()) {
+		return nil, false
+	}
+
+	b := make([]byte, 16)
+	reflect.Copy(reflect.ValueOf(b), rv)
+	if format == UUIDBinary {
+		return b, true
+	}
+	return formatUUID(b), true
+}
+
+func formatUUID(b []byte) string {
+	s := hex.EncodeToString(b)
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// createUUID sets random version 4 UUIDs for blank UUID fields defaulting to generated UUIDs,
+// so that created records carry their keys and gorm doesn't insert SYSUUID as a string.
+func createUUID(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	var fields []*schema.Field
+	for _, field := range db.Statement.Schema.Fields {
+		if hasUUIDDefault(field) {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	setUUIDs := func(rv reflect.Value) {
+		for _, field := range fields {
+			if _, isZero := field.ValueOf(db.Statement.Context, rv); !isZero {
+				continue
+			}
+
+			b := make([]byte, 16)
+			if _, err := rand.Read(b); err != nil {
+				db.AddError(err)
+				return
+			}
+			b[6], b[8] = b[6]&0x0f|0x40, b[8]&0x3f|0x80
+
+			fieldValue := field.ReflectValueOf(db.Statement.Context, rv)
+			switch fieldValue.Kind() {
+			case reflect.Array:
+				reflect.Copy(fieldValue, reflect.ValueOf(b))
+			case reflect.String:
+				fieldValue.SetString(formatUUID(b))
+			case reflect.Slice:
+				fieldValue.SetBytes(b)
+			}
+		}
+	}
+
+	switch rv := db.Statement.ReflectValue; rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			setUUIDs(reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		setUUIDs(rv)
+	}
+}
@@ -0,0 +1,151 @@
+package hdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Vector is an embedding stored in REAL_VECTOR columns of the HANA Cloud vector engine. The
+// dimension is set with the size tag.
+//
+//	Embedding hdb.Vector `gorm:"size:1536"`
+type Vector []float32
+
+// GormDataType implements the schema.GormDataTypeInterface interface.
+func (Vector) GormDataType() string {
+	return "REAL_VECTOR"
+}
+
+// GormValue writes the vector with TO_REAL_VECTOR from its binary representation.
+func (v Vector) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	if v == nil {
+		return clause.Expr{SQL: "NULL"}
+	}
+	return clause.Expr{SQL: "TO_REAL_VECTOR(?)", Vars: []interface{}{binaryValue(v.binary())}}
+}
+
+// Value passes the vector in its binary representation, for use with TO_REAL_VECTOR(?).
+func (v Vector) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return v.binary(), nil
+}
+
+// Scan reads the vector from its binary representation or from its text representation
+// like [0.1,0.2], as returned by TO_NVARCHAR.
+func (v *Vector) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case string:
+		return v.scanText(s)
+	case []byte:
+		if len(s) > 0 && s[0] == '[' {
+			return v.scanText(string(s))
+		}
+		return v.scanBinary(s)
+	}
+	return fmt.Errorf("hdb: cannot scan %T into Vector", src)
+}
+
+// binary returns the vector as the little endian dimension followed by its components as
+// little endian 32-bit floats.
+func (v Vector) binary() []byte {
+	b := make([]byte, 4+4*len(v))
+	binary.LittleEndian.PutUint32(b, uint32(len(v)))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(b[4+4*i:], math.Float32bits(f))
+	}
+	return b
+}
+
+func (v *Vector) scanBinary(b []byte) error {
+	if len(b) < 4 {
+		return errors.New("hdb: invalid binary vector")
+	}
+	n := int(binary.LittleEndian.Uint32(b))
+	if len(b) != 4+4*n {
+		return errors.New("hdb: invalid binary vector")
+	}
+
+	vector := make(Vector, n)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4+4*i:]))
+	}
+	*v = vector
+	return nil
+}
+
+func (v *Vector) scanText(s string) error {
+	var vector Vector
+	if err := json.Unmarshal([]byte(s), &vector); err != nil {
+		return fmt.Errorf("hdb: invalid vector %q: %w", s, err)
+	}
+	*v = vector
+	return nil
+}
+
+// VectorFunction is a COSINE_SIMILARITY or L2DISTANCE expression comparing a REAL_VECTOR
+// column with a query vector.
+type VectorFunction struct {
+	function string
+	column   string
+	vector   Vector
+}
+
+// CosineSimilarity compares column with vector by their cosine similarity, 1 for vectors
+// pointing the same way.
+//
+//	db.Select("*, ? AS SCORE", hdb.CosineSimilarity("embedding", query)).Find(&chunks)
+func CosineSimilarity(column string, vector Vector) VectorFunction {
+	return VectorFunction{function: "COSINE_SIMILARITY", column: column, vector: vector}
+}
+
+// L2Distance compares column with vector by their euclidean distance.
+func L2Distance(column string, vector Vector) VectorFunction {
+	return VectorFunction{function: "L2DISTANCE", column: column, vector: vector}
+}
+
+// Build implements clause.Expression.
+func (f VectorFunction) Build(builder clause.Builder) {
+	builder.WriteString(f.function)
+	builder.WriteByte('(')
+	builder.WriteQuoted(clause.Column{Name: f.column})
+	builder.WriteString(", ")
+	builder.AddVar(builder, f.vector)
+	builder.WriteByte(')')
+}
+
+// NearestNeighbors returns a scope selecting the k rows whose column is most similar to
+// vector by cosine similarity, most similar first.
+//
+//	db.Scopes(hdb.NearestNeighbors("embedding", query, 5)).Find(&chunks)
+func NearestNeighbors(column string, vector Vector, k int) func(*gorm.DB) *gorm.DB {
+	return nearest(CosineSimilarity(column, vector), true, k)
+}
+
+// NearestNeighborsL2 returns a scope selecting the k rows whose column is closest to vector
+// by euclidean distance, closest first.
+func NearestNeighborsL2(column string, vector Vector, k int) func(*gorm.DB) *gorm.DB {
+	return nearest(L2Distance(column, vector), false, k)
+}
+
+func nearest(f VectorFunction, desc bool, k int) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		sql := "?"
+		if desc {
+			sql += " DESC"
+		}
+		return db.Order(clause.OrderBy{Expression: clause.Expr{SQL: sql, Vars: []interface{}{f}}}).Limit(k)
+	}
+}
@@ -0,0 +1,104 @@
+package hdb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestVectorBinary(t *testing.T) {
+	vector := Vector{1, -2, 0.5}
+	want := []byte{
+		3, 0, 0, 0, // dimension
+		0x00, 0x00, 0x80, 0x3f, // 1
+		0x00, 0x00, 0x00, 0xc0, // -2
+		0x00, 0x00, 0x00, 0x3f, // 0.5
+	}
+
+	value, err := vector.Value()
+	if err != nil {
+		t.Fatalf("failed to convert vector: %v", err)
+	}
+	if b, _ := value.([]byte); !bytes.Equal(b, want) {
+		t.Errorf("got % x, want % x", value, want)
+	}
+
+	var scanned Vector
+	if err := scanned.Scan(want); err != nil {
+		t.Fatalf("failed to scan vector: %v", err)
+	}
+	if !reflect.DeepEqual(scanned, vector) {
+		t.Errorf("got %v, want %v", scanned, vector)
+	}
+}
+
+func TestVectorScan(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    interface{}
+		vector Vector
+		err    bool
+	}{
+		{name: "text", src: "[0.5,1.25]", vector: Vector{0.5, 1.25}},
+		{name: "text bytes", src: []byte("[0.5, 1.25]"), vector: Vector{0.5, 1.25}},
+		{name: "empty", src: []byte{0, 0, 0, 0}, vector: Vector{}},
+		{name: "NULL", src: nil},
+		{name: "short", src: []byte{1, 0}, err: true},
+		{name: "dimension", src: []byte{2, 0, 0, 0, 0, 0, 0x80, 0x3f}, err: true},
+		{name: "invalid text", src: "[0.5,", err: true},
+		{name: "number", src: 1.5, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vector := Vector{9}
+			err := vector.Scan(tt.src)
+			if tt.err {
+				if err == nil {
+					t.Errorf("got %v, want an error", vector)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to scan %v: %v", tt.src, err)
+			}
+			if !reflect.DeepEqual(vector, tt.vector) {
+				t.Errorf("got %#v, want %#v", vector, tt.vector)
+			}
+		})
+	}
+}
+
+func TestVectorNull(t *testing.T) {
+	if value, err := Vector(nil).Value(); value != nil || err != nil {
+		t.Errorf("got %v %v, want NULL", value, err)
+	}
+}
+
+type Chunk struct {
+	ID        uint
+	Embedding Vector `gorm:"size:2"`
+}
+
+func TestNearestNeighbors(t *testing.T) {
+	db := dryRunDB(t, Config{})
+	query := Vector{1, 0}
+
+	stmt := db.Scopes(NearestNeighbors("embedding", query, 5)).Find(&[]Chunk{}).Statement
+	if sql := stmt.SQL.String(); sql != `SELECT * FROM "CHUNKS" ORDER BY COSINE_SIMILARITY("EMBEDDING", TO_REAL_VECTOR(?)) DESC LIMIT ?` {
+		t.Errorf("got %s", sql)
+	}
+	if !reflect.DeepEqual(stmt.Vars, []interface{}{binaryValue(query.binary()), 5}) {
+		t.Errorf("got vars %v, want the binary query vector and 5", stmt.Vars)
+	}
+
+	stmt = db.Scopes(NearestNeighborsL2("embedding", query, 3)).Find(&[]Chunk{}).Statement
+	if sql := stmt.SQL.String(); sql != `SELECT * FROM "CHUNKS" ORDER BY L2DISTANCE("EMBEDDING", TO_REAL_VECTOR(?)) LIMIT ?` {
+		t.Errorf("got %s", sql)
+	}
+
+	stmt = db.Select("ID, ? AS SCORE", CosineSimilarity("embedding", query)).Find(&[]Chunk{}).Statement
+	if sql := stmt.SQL.String(); sql != `SELECT ID, COSINE_SIMILARITY("EMBEDDING", TO_REAL_VECTOR(?)) AS SCORE FROM "CHUNKS"` {
+		t.Errorf("got %s", sql)
+	}
+}
@@ -0,0 +1,45 @@
+package hdb
+
+import (
+	"strconv"
+	"strings"
+)
+
+// IsCloud reports whether the detected server is SAP HANA Cloud, which reports versions 4.x and up.
+func (c Config) IsCloud() bool {
+	major, _ := c.version()
+	return major >= 4
+}
+
+// version splits ServerVersion like 2.00.059.00.1636977766 into major version and revision.
+func (c Config) version() (major, revision int) {
+	parts := strings.Split(c.ServerVersion, ".")
+	if len(parts) > 0 {
+		major, _ = strconv.Atoi(parts[0])
+	}
+	if len(parts) > 2 {
+		revision, _ = strconv.Atoi(parts[2])
+	}
+	return
+}
+
+// applyVersion sets the Dont* flags of features the detected server lacks. It only raises
+// flags, so features turned off in the Config stay off on any server.
+func (c *Config) applyVersion() {
+	major, revision := c.version()
+	switch {
+	case major == 1:
+		c.DontSupportRenameIndex = true
+		c.DontSupportForShareClause = true
+		c.DontSupportNSE = true
+		c.DontSupportJSONTable = true
+		c.DontSupportReplaceView = true
+		c.DontSupportReplaceProcedure = true
+		c.DontSupportIdentity = c.DontSupportIdentity || revision < 80
+	case major == 2:
+		c.DontSupportForShareClause = c.DontSupportForShareClause || revision < 30
+		c.DontSupportNSE = c.DontSupportNSE || revision < 40
+		c.DontSupportJSONTable = c.DontSupportJSONTable || revision < 40
+		c.DontSupportReplaceView = c.DontSupportReplaceView || revision < 40
+	}
+}
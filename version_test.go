@@ -0,0 +1,71 @@
+package hdb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		want    Config
+		isCloud bool
+	}{
+		{
+			name:   "HANA 1.0 SPS 12",
+			config: Config{ServerVersion: "1.00.122.00.1234567890"},
+			want: Config{
+				DontSupportRenameIndex: true, DontSupportForShareClause: true, DontSupportNSE: true, DontSupportJSONTable: true,
+				DontSupportReplaceView: true, DontSupportReplaceProcedure: true,
+			},
+		},
+		{
+			name:   "HANA 1.0 SPS 07",
+			config: Config{ServerVersion: "1.00.070.00.1234567890"},
+			want: Config{
+				DontSupportRenameIndex: true, DontSupportForShareClause: true, DontSupportNSE: true, DontSupportJSONTable: true,
+				DontSupportReplaceView: true, DontSupportReplaceProcedure: true, DontSupportIdentity: true,
+			},
+		},
+		{
+			name:   "HANA 2.0 SPS 02",
+			config: Config{ServerVersion: "2.00.024.00.1234567890"},
+			want:   Config{DontSupportForShareClause: true, DontSupportNSE: true, DontSupportJSONTable: true, DontSupportReplaceView: true},
+		},
+		{
+			name:   "HANA 2.0 SPS 05",
+			config: Config{ServerVersion: "2.00.059.00.1636977766"},
+		},
+		{
+			name:    "HANA Cloud",
+			config:  Config{ServerVersion: "4.00.000.00.1700000000"},
+			isCloud: true,
+		},
+		{
+			name:   "flags of the config stay set",
+			config: Config{ServerVersion: "2.00.059.00.1636977766", DontSupportNSE: true, DontSupportJSONTable: true},
+			want:   Config{DontSupportNSE: true, DontSupportJSONTable: true},
+		},
+		{
+			name:    "flags of the config stay set on HANA Cloud",
+			config:  Config{ServerVersion: "4.00.000.00.1700000000", DontSupportReplaceView: true},
+			want:    Config{DontSupportReplaceView: true},
+			isCloud: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			config.applyVersion()
+			tt.want.ServerVersion = tt.config.ServerVersion
+			if !reflect.DeepEqual(config, tt.want) {
+				t.Errorf("got %+v, want %+v", config, tt.want)
+			}
+			if isCloud := config.IsCloud(); isCloud != tt.isCloud {
+				t.Errorf("got IsCloud %v, want %v", isCloud, tt.isCloud)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package hdb
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// HistoryTabler makes a model's table system-versioned, with HANA keeping the former
+// versions of its rows in the history table named by HistoryTable. The model tags the
+// TIMESTAMP fields bounding the validity of a row version with rowStart and rowEnd:
+//
+//	type Account struct {
+//		ID        uint
+//		Balance   int
+//		ValidFrom time.Time `gorm:"rowStart"`
+//		ValidTo   time.Time `gorm:"rowEnd"`
+//	}
+//
+//	func (Account) HistoryTable() string { return "ACCOUNTS_HISTORY" }
+type HistoryTabler interface {
+	HistoryTable() string
+}
+
+// historyTableOf returns the history table of the statement's model, if it implements
+// HistoryTabler.
+func historyTableOf(stmt *gorm.Statement) (string, bool) {
+	if stmt.Schema == nil {
+		return "", false
+	}
+	if tabler, ok := reflect.New(stmt.Schema.ModelType).Interface().(HistoryTabler); ok {
+		return tabler.HistoryTable(), true
+	}
+	return "", false
+}
+
+// systemTimeOf returns START or END for fields tagged with rowStart or rowEnd, whose columns
+// HANA sets to the start and end of the validity of a row version as GENERATED ALWAYS AS
+// ROW START or END.
+func systemTimeOf(field *schema.Field) string {
+	if _, ok := field.TagSettings["ROWSTART"]; ok {
+		return "START"
+	}
+	if _, ok := field.TagSettings["ROWEND"]; ok {
+		return "END"
+	}
+	return ""
+}
+
+// systemTimeFields returns the fields tagged with rowStart and rowEnd of a model.
+func systemTimeFields(s *schema.Schema) (start, end *schema.Field) {
+	for _, field := range s.Fields {
+		switch systemTimeOf(field) {
+		case "START":
+			start = field
+		case "END":
+			end = field
+		}
+	}
+	return start, end
+}
+
+// AsOf returns a scope reading the rows of a system-versioned table as they were at t,
+// from the table or its history table:
+//
+//	db.Scopes(hdb.AsOf(yesterday)).Find(&accounts)
+//	// SELECT * FROM "ACCOUNTS" FOR SYSTEM_TIME AS OF '2024-05-01 12:00:00.0000000'
+func AsOf(t time.Time) func(*gorm.DB) *gorm.DB {
+	return systemTime("AS OF " + systemTimeLiteral(t))
+}
+
+// SystemTimeBetween returns a scope reading the versions of the rows of a system-versioned
+// table valid at any time from from up to but excluding to.
+func SystemTimeBetween(from, to time.Time) func(*gorm.DB) *gorm.DB {
+	return systemTime("FROM " + systemTimeLiteral(from) + " TO " + systemTimeLiteral(to))
+}
+
+func systemTime(spec string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db.Statement.TableExpr = &clause.Expr{SQL: "?", Vars: []interface{}{systemTimeTable{spec: spec}}}
+		return db
+	}
+}
+
+// systemTimeTable is the statement's table read FOR SYSTEM_TIME, whose name is known only
+// once the statement's model is parsed after its scopes.
+type systemTimeTable struct {
+	spec string
+}
+
+func (t systemTimeTable) Build(builder clause.Builder) {
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		stmt.WriteQuoted(clause.Table{Name: stmt.Table})
+	}
+	builder.WriteString(" FOR SYSTEM_TIME " + t.spec)
+}
+
+// systemTimeLiteral returns t as literal in UTC, which HANA keeps system time in.
+func systemTimeLiteral(t time.Time) string {
+	return quoteString(t.UTC().Format("2006-01-02 15:04:05.0000000"))
+}
+
+// applicationTimeFields returns the fields tagged with validFrom and validTo of a model,
+// bounding the period its rows are valid in from the first up to but excluding the second.
+func applicationTimeFields(s *schema.Schema) (from, to *schema.Field) {
+	for _, field := range s.Fields {
+		if _, ok := field.TagSettings["VALIDFROM"]; ok {
+			from = field
+		}
+		if _, ok := field.TagSettings["VALIDTO"]; ok {
+			to = field
+		}
+	}
+	return from, to
+}
+
+// ValidAt returns a scope selecting the rows valid at t by the fields tagged with validFrom
+// and validTo, the period of application time a row is valid in:
+//
+//	type Price struct {
+//		ID        uint
+//		Amount    float64
+//		ValidFrom time.Time `gorm:"type:DATE;validFrom"`
+//		ValidTo   time.Time `gorm:"type:DATE;validTo"`
+//	}
+//
+//	db.Scopes(hdb.ValidAt(day)).Find(&prices)
+//	// SELECT * FROM "PRICES" WHERE "PRICES"."VALID_FROM" <= ? AND "PRICES"."VALID_TO" > ?
+func ValidAt(t time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(applicationTime{from: t})
+	}
+}
+
+// ValidDuring returns a scope selecting the rows valid at any time from from up to but
+// excluding to by the fields tagged with validFrom and validTo.
+func ValidDuring(from, to time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(applicationTime{from: from, to: to, period: true})
+	}
+}
+
+// applicationTime is the condition selecting the rows valid at from, or during the period
+// from from to to, built once the statement's model is parsed after its scopes.
+type applicationTime struct {
+	from, to time.Time
+	period   bool
+}
+
+func (t applicationTime) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
+	}
+
+	var validFrom, validTo *schema.Field
+	if stmt.Schema != nil {
+		validFrom, validTo = applicationTimeFields(stmt.Schema)
+	}
+	if validFrom == nil || validTo == nil {
+		stmt.AddError(fmt.Errorf("the model of %s needs fields tagged validFrom and validTo", stmt.Table))
+		return
+	}
+
+	until := t.from
+	if t.period {
+		until = t.to
+	}
+
+	builder.WriteQuoted(clause.Column{Table: clause.CurrentTable, Name: validFrom.DBName})
+	if t.period {
+		builder.WriteString(" < ")
+	} else {
+		builder.WriteString(" <= ")
+	}
+	builder.AddVar(builder, until)
+	builder.WriteString(" AND ")
+	builder.WriteQuoted(clause.Column{Table: clause.CurrentTable, Name: validTo.DBName})
+	builder.WriteString(" > ")
+	builder.AddVar(builder, t.from)
+}
@@ -0,0 +1,68 @@
+package hdb
+
+import (
+	"sort"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ViewArguments returns a scope reading the statement's parameterized view with the input
+// parameters of args by name, the parameters left out taking their defaults:
+//
+//	db.Scopes(hdb.ViewArguments(map[string]interface{}{"SINCE": day})).Find(&orders)
+//	// SELECT * FROM "ORDERS_SINCE"(SINCE => ?)
+func ViewArguments(args map[string]interface{}) func(*gorm.DB) *gorm.DB {
+	return viewArguments(args, "", "")
+}
+
+// Placeholders returns a scope reading the statement's view with the input parameters of
+// values passed as PLACEHOLDER, the syntax of calculation views. Views set with Table, like
+// calculation views named in raw SQL, keep their expression:
+//
+//	db.Table("?", clause.Table{Name: `"_SYS_BIC"."sales/SALES_BY_REGION"`, Raw: true}).
+//		Scopes(hdb.Placeholders(map[string]interface{}{"P_YEAR": 2024})).Find(&sales)
+//	// SELECT * FROM "_SYS_BIC"."sales/SALES_BY_REGION"(PLACEHOLDER."$$P_YEAR$$" => ?)
+func Placeholders(values map[string]interface{}) func(*gorm.DB) *gorm.DB {
+	return viewArguments(values, `PLACEHOLDER."$$`, `$$"`)
+}
+
+func viewArguments(args map[string]interface{}, prefix, suffix string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		table := parameterizedTable{view: db.Statement.TableExpr, args: args, prefix: prefix, suffix: suffix}
+		db.Statement.TableExpr = &clause.Expr{SQL: "?", Vars: []interface{}{table}}
+		return db
+	}
+}
+
+// parameterizedTable is the statement's view read with input parameters, whose name is
+// known only once the statement's model is parsed after its scopes unless set with Table.
+type parameterizedTable struct {
+	view           *clause.Expr
+	args           map[string]interface{}
+	prefix, suffix string
+}
+
+func (t parameterizedTable) Build(builder clause.Builder) {
+	if t.view != nil {
+		t.view.Build(builder)
+	} else if stmt, ok := builder.(*gorm.Statement); ok {
+		stmt.WriteQuoted(clause.Table{Name: stmt.Table})
+	}
+
+	names := make([]string, 0, len(t.args))
+	for name := range t.args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	builder.WriteByte('(')
+	for idx, name := range names {
+		if idx > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(t.prefix + name + t.suffix + " => ")
+		builder.AddVar(builder, t.args[name])
+	}
+	builder.WriteByte(')')
+}